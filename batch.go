@@ -0,0 +1,132 @@
+package okrforjira
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	defaultIDBatchSize      = 50
+	defaultBatchConcurrency = 1
+)
+
+// WithIDBatchSize sets the maximum number of IDs sent in a single request by
+// ObjectivesByIDs and KeyResultsByIDs. Longer lists are split in several
+// requests whose responses are merged. The default is 50.
+func WithIDBatchSize(size int) Option {
+	return func(c *config) {
+		c.idBatchSize = size
+	}
+}
+
+// WithBatchConcurrency sets the maximum number of requests sent concurrently
+// when a query is split in several requests, see WithIDBatchSize and
+// WithDateSplitter. The default is 1, i.e. the
+// requests are sent one after the other.
+func WithBatchConcurrency(concurrency int) Option {
+	return func(c *config) {
+		c.batchConcurrency = concurrency
+	}
+}
+
+// MergeResponses merges the responses into a single one.
+// The OKRs, key results, teams, periods and labels are deduplicated by ID,
+// keeping the first occurrence.
+func MergeResponses(responses ...Response) Response {
+	var merged Response
+	okrs := make(map[string]bool)
+	keyResults := make(map[string]bool)
+	teams := make(map[string]bool)
+	periods := make(map[string]bool)
+	labels := make(map[string]bool)
+	for _, r := range responses {
+		merged.OKRs = appendUnique(merged.OKRs, r.OKRs, okrs, func(o OKR) string { return o.ID })
+		merged.KeyResults = appendUnique(merged.KeyResults, r.KeyResults, keyResults, func(kr KeyResult) string { return kr.ID })
+		merged.Teams = appendUnique(merged.Teams, r.Teams, teams, func(t Team) string { return t.ID })
+		merged.Periods = appendUnique(merged.Periods, r.Periods, periods, func(p Period) string { return p.ID })
+		merged.Labels = appendUnique(merged.Labels, r.Labels, labels, func(l Label) string { return l.ID })
+	}
+	return merged
+}
+
+func appendUnique[T any](dst, src []T, seen map[string]bool, id func(T) string) []T {
+	if dst == nil && src != nil {
+		dst = make([]T, 0, len(src))
+	}
+	for _, item := range src {
+		if seen[id(item)] {
+			continue
+		}
+		seen[id(item)] = true
+		dst = append(dst, item)
+	}
+	return dst
+}
+
+// splitIDs splits ids into batches of at most size IDs.
+func splitIDs(ids []string, size int) [][]string {
+	if size <= 0 || len(ids) <= size {
+		return [][]string{ids}
+	}
+	batches := make([][]string, 0, (len(ids)+size-1)/size)
+	for len(ids) > size {
+		batches = append(batches, ids[:size])
+		ids = ids[size:]
+	}
+	return append(batches, ids)
+}
+
+// fetchAll calls fetch for each of the n queries with at most concurrency
+// calls at a time, and merges the responses in the order of the queries.
+// The first error cancels the remaining queries and is returned.
+func fetchAll(ctx context.Context, n, concurrency int, fetch func(ctx context.Context, i int) (Response, error)) (Response, error) {
+	if n == 1 {
+		return fetch(ctx, 0)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make([]Response, n)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			response, err := fetch(ctx, i)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			responses[i] = response
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return Response{}, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return Response{}, err
+	}
+	return MergeResponses(responses...), nil
+}
@@ -0,0 +1,113 @@
+package okrforjira_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeResponses(t *testing.T) {
+	r1 := okrforjira.Response{
+		OKRs:       []okrforjira.OKR{{ID: "o1", Key: "O-1"}},
+		KeyResults: []okrforjira.KeyResult{{ID: "kr1"}, {ID: "kr2"}},
+		Teams:      []okrforjira.Team{{ID: "t1", Name: "Team 1"}},
+		Periods:    []okrforjira.Period{},
+	}
+	r2 := okrforjira.Response{
+		OKRs:       []okrforjira.OKR{{ID: "o2", Key: "O-2"}, {ID: "o1", Key: "duplicate"}},
+		KeyResults: []okrforjira.KeyResult{{ID: "kr2"}, {ID: "kr3"}},
+		Teams:      []okrforjira.Team{{ID: "t1", Name: "duplicate"}, {ID: "t2", Name: "Team 2"}},
+		Labels:     []okrforjira.Label{{ID: "l1"}},
+	}
+
+	got := okrforjira.MergeResponses(r1, r2)
+	want := okrforjira.Response{
+		OKRs:       []okrforjira.OKR{{ID: "o1", Key: "O-1"}, {ID: "o2", Key: "O-2"}},
+		KeyResults: []okrforjira.KeyResult{{ID: "kr1"}, {ID: "kr2"}, {ID: "kr3"}},
+		Teams:      []okrforjira.Team{{ID: "t1", Name: "Team 1"}, {ID: "t2", Name: "Team 2"}},
+		Periods:    []okrforjira.Period{},
+		Labels:     []okrforjira.Label{{ID: "l1"}},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestClient_IDBatches(t *testing.T) {
+	// The fake server returns one objective per requested ID,
+	// and the same team for every request.
+	newServer := func(mu *sync.Mutex, queries *[]string) *http.Client {
+		return NewTestClient(func(req *http.Request) *http.Response {
+			param := req.URL.Query().Get("objectiveIds")
+			if param == "" {
+				param = req.URL.Query().Get("keyResultIds")
+			}
+			mu.Lock()
+			*queries = append(*queries, param)
+			mu.Unlock()
+			var okrs []string
+			for _, id := range strings.Split(param, ",") {
+				okrs = append(okrs, fmt.Sprintf(`{"id":%q}`, id))
+			}
+			return newJSONResponse(200, `{"okrs":[`+strings.Join(okrs, ",")+`],"teams":[{"id":"t1","name":"Team"}]}`)
+		})
+	}
+	ids := []string{"1", "2", "3", "4", "5"}
+	ctx := context.Background()
+
+	t.Run("IDs are split in batches", func(t *testing.T) {
+		var mu sync.Mutex
+		var queries []string
+		c := okrforjira.NewClient(newServer(&mu, &queries), token, okrforjira.WithIDBatchSize(2))
+		got, err := c.ObjectivesByIDs(ctx, ids, []string{"TEAMS"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1,2", "3,4", "5"}, queries)
+		assert.Equal(t, []okrforjira.OKR{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}}, got.OKRs)
+		assert.Equal(t, []okrforjira.Team{{ID: "t1", Name: "Team"}}, got.Teams)
+	})
+
+	t.Run("Batches are fetched concurrently", func(t *testing.T) {
+		var mu sync.Mutex
+		var queries []string
+		var inFlight, maxInFlight int32
+		server := newServer(&mu, &queries)
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			r, _ := server.Transport.RoundTrip(req)
+			return r
+		})
+		c := okrforjira.NewClient(client, token, okrforjira.WithIDBatchSize(1), okrforjira.WithBatchConcurrency(2))
+		got, err := c.KeyResultsByIDs(ctx, ids, nil)
+		assert.NoError(t, err)
+		assert.Len(t, queries, 5)
+		assert.Equal(t, int32(2), maxInFlight)
+		// The order of the IDs is preserved.
+		assert.Equal(t, []okrforjira.OKR{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}}, got.OKRs)
+	})
+
+	t.Run("The first error stops the batches", func(t *testing.T) {
+		var requests int32
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			atomic.AddInt32(&requests, 1)
+			return newJSONResponse(500, `{}`)
+		})
+		c := okrforjira.NewClient(client, token, okrforjira.WithIDBatchSize(1))
+		_, err := c.ObjectivesByIDs(ctx, ids, nil)
+		assert.ErrorIs(t, err, okrforjira.ErrServer)
+		assert.Equal(t, int32(1), requests)
+	})
+}
@@ -0,0 +1,186 @@
+package okrforjira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CheckIn is an update of an objective or a key result, e.g. a row of a
+// bulk update file.
+type CheckIn struct {
+	// Ref is the key, e.g. "KR-8", or the ID of the objective or key result.
+	Ref    string
+	Status Status
+	// Value is the new value of a key result. It must be null for an objective.
+	Value       NullFloat64
+	Description string
+}
+
+// EntityKind is the kind of entity updated by a check-in.
+type EntityKind string
+
+// The kinds of entities.
+const (
+	EntityObjective EntityKind = "OBJECTIVE"
+	EntityKeyResult EntityKind = "KEY_RESULT"
+)
+
+// PlannedCheckIn is a check-in resolved and validated by PlanCheckIns.
+type PlannedCheckIn struct {
+	CheckIn
+	// Kind, ID, Key and Name describe the updated entity when it was found.
+	Kind EntityKind
+	ID   string
+	Key  string
+	Name string
+	// Previous is the latest update of the entity before the check-in.
+	Previous Update
+	// Err is the validation error of the check-in, if any.
+	Err error
+}
+
+// CheckInPlan is a list of check-ins ready to be applied with ApplyCheckIns.
+type CheckInPlan struct {
+	CheckIns []PlannedCheckIn
+}
+
+// Invalid returns the number of check-ins with a validation error.
+func (p *CheckInPlan) Invalid() int {
+	n := 0
+	for _, c := range p.CheckIns {
+		if c.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Err returns the validation errors of the check-ins, or nil if they are all valid.
+func (p *CheckInPlan) Err() error {
+	var msgs []string
+	for i, c := range p.CheckIns {
+		if c.Err != nil {
+			msgs = append(msgs, fmt.Sprintf("check-in %d (%s): %s", i+1, c.Ref, c.Err))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidCheckIn, strings.Join(msgs, "; "))
+}
+
+// ErrInvalidCheckIn is returned by ApplyCheckIns when a check-in of the plan is invalid.
+var ErrInvalidCheckIn = errors.New("invalid check-in")
+
+// PlanCheckIns resolves the objectives and key results of the check-ins, see
+// Lookup, and validates the check-ins: the status must be valid, a key result
+// must have a value and an objective must not, and an entity can only be
+// updated once. The invalid check-ins have an Err. The returned error is only
+// set when the entities could not be looked up.
+func (c *Client) PlanCheckIns(ctx context.Context, checkIns []CheckIn, search DateRange) (*CheckInPlan, error) {
+	var refs []string
+	for _, checkIn := range checkIns {
+		if checkIn.Ref != "" {
+			refs = append(refs, checkIn.Ref)
+		}
+	}
+	found, err := c.Lookup(ctx, refs, search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan the check-ins: %w", err)
+	}
+	idx := NewIndex(found)
+
+	plan := &CheckInPlan{CheckIns: make([]PlannedCheckIn, len(checkIns))}
+	updated := make(map[string]int)
+	for i, checkIn := range checkIns {
+		planned := PlannedCheckIn{CheckIn: checkIn}
+		planned.Err = planned.resolve(idx)
+		if planned.Err == nil {
+			if first, ok := updated[planned.ID]; ok {
+				planned.Err = fmt.Errorf("%s is already updated by check-in %d", planned.Key, first+1)
+			} else {
+				updated[planned.ID] = i
+			}
+		}
+		plan.CheckIns[i] = planned
+	}
+	return plan, nil
+}
+
+// resolve fills the entity of the check-in and validates it.
+func (p *PlannedCheckIn) resolve(idx *Index) error {
+	if p.Ref == "" {
+		return errors.New("missing key or ID")
+	}
+	status, err := ParseStatus(string(p.Status))
+	if err != nil {
+		return err
+	}
+	p.Status = status
+	if kr, ok := idx.LookupKeyResult(p.Ref); ok {
+		p.Kind, p.ID, p.Key, p.Name, p.Previous = EntityKeyResult, kr.ID, kr.Key, kr.Name, kr.LatestUpdate
+		if !p.Value.Valid {
+			return fmt.Errorf("missing value of key result %s", kr.Key)
+		}
+		return nil
+	}
+	if okr, ok := idx.LookupOKR(p.Ref); ok {
+		p.Kind, p.ID, p.Key, p.Name, p.Previous = EntityObjective, okr.ID, okr.Key, okr.Name, okr.LatestUpdate
+		if p.Value.Valid {
+			return fmt.Errorf("objective %s cannot have a value", okr.Key)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s: %w", p.Ref, ErrNotFound)
+}
+
+// CheckInResult is the result of a check-in applied by ApplyCheckIns.
+type CheckInResult struct {
+	PlannedCheckIn
+	// Update is the update returned by the API when the check-in succeeded.
+	Update Update
+	// ApplyErr is the error of the update, if any. The embedded Err is the
+	// validation error of the plan, which is always nil here.
+	ApplyErr error
+}
+
+// ApplyCheckIns sends the check-ins of the plan with at most concurrency
+// requests at a time, and returns their results in the order of the plan.
+// A failed check-in does not stop the others. Nothing is sent and an error
+// wrapping ErrInvalidCheckIn is returned if a check-in of the plan is invalid.
+func (c *Client) ApplyCheckIns(ctx context.Context, plan *CheckInPlan, concurrency int) ([]CheckInResult, error) {
+	if err := plan.Err(); err != nil {
+		return nil, err
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]CheckInResult, len(plan.CheckIns))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, checkIn := range plan.CheckIns {
+		results[i].PlannedCheckIn = checkIn
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(result *CheckInResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result.Update, result.ApplyErr = c.applyCheckIn(ctx, result.PlannedCheckIn)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func (c *Client) applyCheckIn(ctx context.Context, p PlannedCheckIn) (Update, error) {
+	if err := ctx.Err(); err != nil {
+		return Update{}, err
+	}
+	if p.Kind == EntityKeyResult {
+		return c.UpdateKeyResult(ctx, p.ID, p.Status, p.Value.Float64, p.Description)
+	}
+	return c.UpdateObjective(ctx, p.ID, p.Status, p.Description)
+}
@@ -0,0 +1,119 @@
+package okrforjira_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/grandper/okrforjira/okrforjiratest"
+	"github.com/stretchr/testify/assert"
+)
+
+var checkInSearch = okrforjira.DateRange{
+	StartDate: time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC),
+	Deadline:  time.Date(2022, time.December, 31, 0, 0, 0, 0, time.UTC),
+}
+
+func TestClient_PlanCheckIns(t *testing.T) {
+	s := okrforjiratest.NewServer(okrforjiratest.DefaultSeed())
+	defer s.Close()
+	c := s.Client()
+
+	plan, err := c.PlanCheckIns(context.Background(), []okrforjira.CheckIn{
+		{Ref: "KR-1", Status: "at risk", Value: okrforjira.NewNullFloat64(4500), Description: "Slower growth"},
+		{Ref: "o2", Status: okrforjira.StatusDelayed, Description: "Blocked"},
+		{Ref: "KR-3", Status: okrforjira.StatusOnTrack},
+		{Ref: "O-1", Status: okrforjira.StatusOnTrack, Value: okrforjira.NewNullFloat64(1)},
+		{Ref: "KR-9", Status: okrforjira.StatusOnTrack, Value: okrforjira.NewNullFloat64(1)},
+		{Ref: "kr1", Status: okrforjira.StatusOnTrack, Value: okrforjira.NewNullFloat64(5000)},
+		{Ref: "KR-4", Status: "GREEN", Value: okrforjira.NewNullFloat64(35)},
+		{Status: okrforjira.StatusOnTrack},
+	}, checkInSearch)
+	assert.NoError(t, err)
+	if !assert.Len(t, plan.CheckIns, 8) {
+		return
+	}
+
+	kr1 := plan.CheckIns[0]
+	assert.NoError(t, kr1.Err)
+	assert.Equal(t, okrforjira.EntityKeyResult, kr1.Kind)
+	assert.Equal(t, "kr1", kr1.ID)
+	assert.Equal(t, "Reach 10000 active users", kr1.Name)
+	assert.Equal(t, okrforjira.StatusAtRisk, kr1.Status)
+	assert.Equal(t, okrforjira.StatusOnTrack, kr1.Previous.Status)
+	assert.Equal(t, okrforjira.NewNullFloat64(4000), kr1.Previous.Value)
+
+	o2 := plan.CheckIns[1]
+	assert.NoError(t, o2.Err)
+	assert.Equal(t, okrforjira.EntityObjective, o2.Kind)
+	assert.Equal(t, "O-2", o2.Key)
+
+	assert.EqualError(t, plan.CheckIns[2].Err, "missing value of key result KR-3")
+	assert.EqualError(t, plan.CheckIns[3].Err, "objective O-1 cannot have a value")
+	assert.ErrorIs(t, plan.CheckIns[4].Err, okrforjira.ErrNotFound)
+	assert.EqualError(t, plan.CheckIns[5].Err, "KR-1 is already updated by check-in 1")
+	assert.Error(t, plan.CheckIns[6].Err)
+	assert.EqualError(t, plan.CheckIns[7].Err, "missing key or ID")
+
+	assert.Equal(t, 6, plan.Invalid())
+	err = plan.Err()
+	assert.ErrorIs(t, err, okrforjira.ErrInvalidCheckIn)
+	assert.Contains(t, err.Error(), "check-in 3 (KR-3): missing value of key result KR-3")
+
+	_, err = c.ApplyCheckIns(context.Background(), plan, 2)
+	assert.ErrorIs(t, err, okrforjira.ErrInvalidCheckIn)
+	for _, r := range s.Requests() {
+		assert.Equal(t, http.MethodGet, r.Method, r.Path)
+	}
+}
+
+func TestClient_ApplyCheckIns(t *testing.T) {
+	s := okrforjiratest.NewServer(okrforjiratest.DefaultSeed())
+	defer s.Close()
+	c := s.Client(okrforjira.WithRetryPolicy(okrforjira.RetryPolicy{}))
+	ctx := context.Background()
+
+	plan, err := c.PlanCheckIns(ctx, []okrforjira.CheckIn{
+		{Ref: "KR-1", Status: okrforjira.StatusAtRisk, Value: okrforjira.NewNullFloat64(4500), Description: "Slower growth"},
+		{Ref: "KR-3", Status: okrforjira.StatusOnTrack, Value: okrforjira.NewNullFloat64(12), Description: "Fewer incidents"},
+		{Ref: "O-2", Status: okrforjira.StatusDelayed, Description: "Blocked"},
+	}, checkInSearch)
+	assert.NoError(t, err)
+	assert.NoError(t, plan.Err())
+	assert.Zero(t, plan.Invalid())
+
+	s.InjectFault(okrforjiratest.Fault{
+		Method:     http.MethodPost,
+		Path:       okrforjiratest.KeyResultUpdatePath,
+		StatusCode: http.StatusInternalServerError,
+		Times:      1,
+	})
+	results, err := c.ApplyCheckIns(ctx, plan, 1)
+	assert.NoError(t, err)
+	if !assert.Len(t, results, 3) {
+		return
+	}
+
+	var apiErr *okrforjira.APIError
+	assert.True(t, errors.As(results[0].ApplyErr, &apiErr), results[0].ApplyErr)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "KR-1", results[0].Key)
+
+	assert.NoError(t, results[1].ApplyErr)
+	assert.Equal(t, "kr3", results[1].Update.EntityID)
+	assert.Equal(t, okrforjira.NewNullFloat64(12), results[1].Update.Value)
+
+	assert.NoError(t, results[2].ApplyErr)
+	assert.Equal(t, okrforjira.StatusDelayed, results[2].Update.Status)
+
+	got := okrforjira.NewIndex(s.Response())
+	kr1, _ := got.KeyResult("kr1")
+	assert.Equal(t, okrforjira.StatusOnTrack, kr1.LatestUpdate.Status)
+	kr3, _ := got.KeyResult("kr3")
+	assert.Equal(t, "Fewer incidents", kr3.LatestUpdate.Description)
+	o2, _ := got.OKR("o2")
+	assert.Equal(t, "Blocked", o2.LatestUpdate.Description)
+}
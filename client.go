@@ -4,39 +4,88 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"strings"
 	"time"
-
-	jsontime "github.com/liamylian/jsontime/v2/v2"
-	"golang.org/x/exp/slices"
 )
 
 // Client is a HTTP client to get data from the OKR for Jira app.
 type Client struct {
-	httpClient *http.Client
-	token      string
+	httpClient   *http.Client
+	token        string
+	baseURL      string
+	userAgent    string
+	extraHeaders http.Header
+	retryPolicy  *RetryPolicy
+	rateLimiter  *RateLimiter
+
+	idBatchSize      int
+	batchConcurrency int
+	dateSplitter     DateSplitter
+
+	location       *time.Location
+	driftReport    *DriftReport
+	strictDecoding bool
 }
 
 // NewClient creates a new OKR for Jira client.
 // If a nil httpClient is provided, a new http.Client will be used.
-func NewClient(httpClient *http.Client, token string) *Client {
+// The client can be further configured with options such as WithBaseURL.
+func NewClient(httpClient *http.Client, token string, opts ...Option) *Client {
+	cfg := config{
+		baseURL: defaultBaseURL,
+		timeout: defaultTimeout,
+
+		idBatchSize:      defaultIDBatchSize,
+		batchConcurrency: defaultBatchConcurrency,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	if httpClient == nil {
-		// Avoid to use the default transport.
-		t := http.DefaultTransport.(*http.Transport).Clone()
-		t.MaxIdleConns = 100
-		t.MaxConnsPerHost = 100
-		t.MaxIdleConnsPerHost = 100
+		transport := cfg.transport
+		if transport == nil {
+			// Avoid to use the default transport.
+			t := http.DefaultTransport.(*http.Transport).Clone()
+			t.MaxIdleConns = 100
+			t.MaxConnsPerHost = 100
+			t.MaxIdleConnsPerHost = 100
+			transport = t
+		}
 		httpClient = &http.Client{
-			Timeout:   10 * time.Second,
-			Transport: t,
+			Timeout:   cfg.timeout,
+			Transport: transport,
+		}
+	} else if cfg.timeoutSet || cfg.transport != nil {
+		// Do not modify the http.Client provided by the caller.
+		hc := *httpClient
+		if cfg.timeoutSet {
+			hc.Timeout = cfg.timeout
 		}
+		if cfg.transport != nil {
+			hc.Transport = cfg.transport
+		}
+		httpClient = &hc
 	}
 
 	return &Client{
-		httpClient: httpClient,
-		token:      token,
+		httpClient:   httpClient,
+		token:        token,
+		baseURL:      strings.TrimSuffix(cfg.baseURL, "/"),
+		userAgent:    cfg.userAgent,
+		extraHeaders: cfg.extraHeaders,
+		retryPolicy:  cfg.retryPolicy,
+		rateLimiter:  cfg.rateLimiter,
+
+		idBatchSize:      cfg.idBatchSize,
+		batchConcurrency: cfg.batchConcurrency,
+		dateSplitter:     cfg.dateSplitter,
+
+		location:       cfg.location,
+		driftReport:    cfg.driftReport,
+		strictDecoding: cfg.strictDecoding,
 	}
 }
 
@@ -58,9 +107,9 @@ type OKR struct {
 	OwnerAccountID         string    `json:"ownerAccountId"`
 	CollaboratorAccountIDs []string  `json:"collaboratorAccountIds"`
 	PercentDone            float64   `json:"percentDone"`
-	Created                time.Time `json:"created" time_format:"okr4j_format"`
-	StartDate              time.Time `json:"startDate" time_format:"okr4j_format"`
-	Deadline               time.Time `json:"deadline" time_format:"okr4j_format"`
+	Created                time.Time `json:"created"`
+	StartDate              time.Time `json:"startDate"`
+	Deadline               time.Time `json:"deadline"`
 	LabelIDs               []string  `json:"labelIds"`
 	TeamIDs                []string  `json:"teamIds"`
 	KRIDs                  []string  `json:"krIds"`
@@ -81,9 +130,9 @@ type KeyResult struct {
 	OwnerAccountID            string             `json:"ownerAccountId"`
 	CollaboratorAccountIds    []string           `json:"collaboratorAccountIds"`
 	PercentDone               float64            `json:"percentDone"`
-	Created                   time.Time          `json:"created" time_format:"okr4j_format"`
-	StartDate                 time.Time          `json:"startDate" time_format:"okr4j_format"`
-	Deadline                  time.Time          `json:"deadline" time_format:"okr4j_format"`
+	Created                   time.Time          `json:"created"`
+	StartDate                 time.Time          `json:"startDate"`
+	Deadline                  time.Time          `json:"deadline"`
 	LabelIDs                  []string           `json:"labelIds"`
 	TeamIDs                   []string           `json:"teamIds"`
 	PeriodAliasID             string             `json:"periodAliasId"`
@@ -94,18 +143,18 @@ type KeyResult struct {
 }
 
 type Update struct {
-	EntityID    string    `json:"entityId"`
-	Status      string    `json:"status"`
-	Created     time.Time `json:"created" time_format:"okr4j_format"`
-	Value       float64   `json:"value"`
-	Description string    `json:"description"`
+	EntityID    string      `json:"entityId"`
+	Status      Status      `json:"status"`
+	Created     time.Time   `json:"created"`
+	Value       NullFloat64 `json:"value"`
+	Description string      `json:"description"`
 }
 
 type ProgressDefinition struct {
-	Type         string  `json:"type"`
-	StartValue   float64 `json:"startValue"`
-	DesiredValue float64 `json:"desiredValue"`
-	JQL          string  `json:"jql"`
+	Type         ProgressType `json:"type"`
+	StartValue   NullFloat64  `json:"startValue"`
+	DesiredValue NullFloat64  `json:"desiredValue"`
+	JQL          string       `json:"jql"`
 }
 
 type Team struct {
@@ -116,8 +165,8 @@ type Team struct {
 type Period struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
-	StartDate time.Time `json:"startDate" time_format:"okr4j_format"`
-	Deadline  time.Time `json:"deadline" time_format:"okr4j_format"`
+	StartDate time.Time `json:"startDate"`
+	Deadline  time.Time `json:"deadline"`
 }
 
 type Label struct {
@@ -130,15 +179,34 @@ type Unit struct {
 	Symbol string `json:"symbol"`
 }
 
+// RateLimiterStats returns the statistics of the rate limiter of the client,
+// or zero statistics if the client has no rate limiter.
+func (c *Client) RateLimiterStats() RateLimiterStats {
+	if c.rateLimiter == nil {
+		return RateLimiterStats{}
+	}
+	return c.rateLimiter.Stats()
+}
+
 // ObjectivesByDate returns a list of objectives which have start date or/and due date inside specified date range.
+// The range can be split in several requests, see WithDateSplitter.
 func (c *Client) ObjectivesByDate(ctx context.Context, startDate, deadline time.Time, expand []string) (Response, error) {
-	startDateEpochMilli := startDate.UnixMilli()
-	deadlineEpochMilli := deadline.UnixMilli()
-	if err := c.checkObject(expand); err != nil {
+	return c.ObjectivesByDateExpand(ctx, startDate, deadline, toExpandObjects(expand)...)
+}
+
+// ObjectivesByDateExpand is like ObjectivesByDate with a typed set of objects to expand.
+func (c *Client) ObjectivesByDateExpand(ctx context.Context, startDate, deadline time.Time, expand ...ExpandObject) (Response, error) {
+	expandParam, err := expandQueryParam(expand)
+	if err != nil {
 		return Response{}, fmt.Errorf("failed to get the obectives by date: %w", err)
 	}
-	url := fmt.Sprintf(objectivesByDateURL, startDateEpochMilli, deadlineEpochMilli, strings.Join(expand, ","))
-	response, err := c.executeGetQuery(url)
+	ranges := c.dateRanges(startDate, deadline)
+	response, err := fetchAll(ctx, len(ranges), c.batchConcurrency, func(ctx context.Context, i int) (Response, error) {
+		startDateEpochMilli := ranges[i].StartDate.UnixMilli()
+		deadlineEpochMilli := ranges[i].Deadline.UnixMilli()
+		url := fmt.Sprintf(c.baseURL+objectivesByDatePath, startDateEpochMilli, deadlineEpochMilli, expandParam)
+		return c.executeGetQuery(ctx, url)
+	})
 	if err != nil {
 		return Response{}, fmt.Errorf("failed to get the obectives by date: %w", err)
 	}
@@ -146,12 +214,22 @@ func (c *Client) ObjectivesByDate(ctx context.Context, startDate, deadline time.
 }
 
 // ObjectivesByIDs returns a list of objectives with specified ids.
+// Long lists of ids are split in several requests, see WithIDBatchSize.
 func (c *Client) ObjectivesByIDs(ctx context.Context, objectiveIDs, expand []string) (Response, error) {
-	if err := c.checkObject(expand); err != nil {
+	return c.ObjectivesByIDsExpand(ctx, objectiveIDs, toExpandObjects(expand)...)
+}
+
+// ObjectivesByIDsExpand is like ObjectivesByIDs with a typed set of objects to expand.
+func (c *Client) ObjectivesByIDsExpand(ctx context.Context, objectiveIDs []string, expand ...ExpandObject) (Response, error) {
+	expandParam, err := expandQueryParam(expand)
+	if err != nil {
 		return Response{}, fmt.Errorf("failed to get the obectives by ids: %w", err)
 	}
-	url := fmt.Sprintf(objectivesByIDsURL, strings.Join(objectiveIDs, ","), strings.Join(expand, ","))
-	response, err := c.executeGetQuery(url)
+	batches := splitIDs(objectiveIDs, c.idBatchSize)
+	response, err := fetchAll(ctx, len(batches), c.batchConcurrency, func(ctx context.Context, i int) (Response, error) {
+		url := fmt.Sprintf(c.baseURL+objectivesByIDsPath, strings.Join(batches[i], ","), expandParam)
+		return c.executeGetQuery(ctx, url)
+	})
 	if err != nil {
 		return Response{}, fmt.Errorf("failed to get the obectives by ids: %w", err)
 	}
@@ -159,14 +237,24 @@ func (c *Client) ObjectivesByIDs(ctx context.Context, objectiveIDs, expand []str
 }
 
 // KeyResultsByDate returns a list of key results which have start date or/and due date inside specified date range.
+// The range can be split in several requests, see WithDateSplitter.
 func (c *Client) KeyResultsByDate(ctx context.Context, startDate, deadline time.Time, expand []string) (Response, error) {
-	startDateEpochMilli := startDate.UnixMilli()
-	deadlineEpochMilli := deadline.UnixMilli()
-	if err := c.checkObject(expand); err != nil {
+	return c.KeyResultsByDateExpand(ctx, startDate, deadline, toExpandObjects(expand)...)
+}
+
+// KeyResultsByDateExpand is like KeyResultsByDate with a typed set of objects to expand.
+func (c *Client) KeyResultsByDateExpand(ctx context.Context, startDate, deadline time.Time, expand ...ExpandObject) (Response, error) {
+	expandParam, err := expandQueryParam(expand)
+	if err != nil {
 		return Response{}, fmt.Errorf("failed to get the obectives by date: %w", err)
 	}
-	url := fmt.Sprintf(keyResultsByDateURL, startDateEpochMilli, deadlineEpochMilli, strings.Join(expand, ","))
-	response, err := c.executeGetQuery(url)
+	ranges := c.dateRanges(startDate, deadline)
+	response, err := fetchAll(ctx, len(ranges), c.batchConcurrency, func(ctx context.Context, i int) (Response, error) {
+		startDateEpochMilli := ranges[i].StartDate.UnixMilli()
+		deadlineEpochMilli := ranges[i].Deadline.UnixMilli()
+		url := fmt.Sprintf(c.baseURL+keyResultsByDatePath, startDateEpochMilli, deadlineEpochMilli, expandParam)
+		return c.executeGetQuery(ctx, url)
+	})
 	if err != nil {
 		return Response{}, fmt.Errorf("failed to get the obectives by date: %w", err)
 	}
@@ -174,12 +262,22 @@ func (c *Client) KeyResultsByDate(ctx context.Context, startDate, deadline time.
 }
 
 // KeyResultsByIDs returns a list of key results with specified ids.
+// Long lists of ids are split in several requests, see WithIDBatchSize.
 func (c *Client) KeyResultsByIDs(ctx context.Context, keyResultIDs, expand []string) (Response, error) {
-	if err := c.checkObject(expand); err != nil {
+	return c.KeyResultsByIDsExpand(ctx, keyResultIDs, toExpandObjects(expand)...)
+}
+
+// KeyResultsByIDsExpand is like KeyResultsByIDs with a typed set of objects to expand.
+func (c *Client) KeyResultsByIDsExpand(ctx context.Context, keyResultIDs []string, expand ...ExpandObject) (Response, error) {
+	expandParam, err := expandQueryParam(expand)
+	if err != nil {
 		return Response{}, fmt.Errorf("failed to get the key results by ids: %w", err)
 	}
-	url := fmt.Sprintf(keyREsultsByIDsURL, strings.Join(keyResultIDs, ","), strings.Join(expand, ","))
-	response, err := c.executeGetQuery(url)
+	batches := splitIDs(keyResultIDs, c.idBatchSize)
+	response, err := fetchAll(ctx, len(batches), c.batchConcurrency, func(ctx context.Context, i int) (Response, error) {
+		url := fmt.Sprintf(c.baseURL+keyResultsByIDsPath, strings.Join(batches[i], ","), expandParam)
+		return c.executeGetQuery(ctx, url)
+	})
 	if err != nil {
 		return Response{}, fmt.Errorf("failed to get the key results by ids: %w", err)
 	}
@@ -193,10 +291,13 @@ type objectiveUpdateRequest struct {
 }
 
 // UpdateObjective update the provided objective.
-func (c *Client) UpdateObjective(ctx context.Context, objectiveID, status, description string) (Update, error) {
+func (c *Client) UpdateObjective(ctx context.Context, objectiveID string, status Status, description string) (Update, error) {
+	if err := status.Validate(); err != nil {
+		return Update{}, fmt.Errorf("failed to update the objective: %w", err)
+	}
 	request := objectiveUpdateRequest{
 		ObjectiveID: objectiveID,
-		Status:      status,
+		Status:      status.updateSpelling(),
 		Description: description,
 	}
 	data, err := json.Marshal(request)
@@ -204,9 +305,12 @@ func (c *Client) UpdateObjective(ctx context.Context, objectiveID, status, descr
 		return Update{}, fmt.Errorf("failed to update the objective: %w", err)
 	}
 	var response Update
-	if err := c.executePostQuery(objectiveUpdateURL, string(data), &response); err != nil {
+	if err := c.executePostQuery(ctx, c.baseURL+objectiveUpdatePath, string(data), &response); err != nil {
 		return Update{}, fmt.Errorf("failed to update the objective: %w", err)
 	}
+	if c.location != nil {
+		response = response.in(c.location)
+	}
 	return response, nil
 }
 
@@ -218,10 +322,13 @@ type keyResultUpdateRequest struct {
 }
 
 // UpdateKeyResult update the provided key result.
-func (c *Client) UpdateKeyResult(ctx context.Context, keyResultID, status string, newValue float64, description string) (Update, error) {
+func (c *Client) UpdateKeyResult(ctx context.Context, keyResultID string, status Status, newValue float64, description string) (Update, error) {
+	if err := status.Validate(); err != nil {
+		return Update{}, fmt.Errorf("failed to update the key result: %w", err)
+	}
 	request := keyResultUpdateRequest{
 		KeyResultID: keyResultID,
-		Status:      status,
+		Status:      status.updateSpelling(),
 		NewValue:    newValue,
 		Description: description,
 	}
@@ -230,91 +337,122 @@ func (c *Client) UpdateKeyResult(ctx context.Context, keyResultID, status string
 		return Update{}, fmt.Errorf("failed to update the key result: %w", err)
 	}
 	var response Update
-	if err := c.executePostQuery(keyResultUpdateURL, string(data), &response); err != nil {
+	if err := c.executePostQuery(ctx, c.baseURL+keyResultUpdatePath, string(data), &response); err != nil {
 		return Update{}, fmt.Errorf("failed to update the key result: %w", err)
 	}
+	if c.location != nil {
+		response = response.in(c.location)
+	}
 	return response, nil
 }
 
 const (
-	host                = "https://okr-for-jira-prod.herokuapp.com"
-	objectivesByDateURL = host + "/api/v2/api-export/objectives/byDate?startDateEpochMilli=%d&deadlineEpochMilli=%d&expand=%s"
-	objectivesByIDsURL  = host + "/api/v2/api-export/objectives/byIds?objectiveIds=%s&expand=%s"
-	keyResultsByDateURL = host + "/api/v2/api-export/keyResults/byDate?startDateEpochMilli=%d&deadlineEpochMilli=%d&expand=%s"
-	keyREsultsByIDsURL  = host + "/api/v2/api-export/keyResults/byIds?keyResultIds=%s&expand=%s"
-	objectiveUpdateURL  = host + "/api/v2/api-update/objectives"
-	keyResultUpdateURL  = host + "/api/v2/api-update/keyResults"
-	dateFormat          = "2006-01-02T15:04:05-0700"
+	defaultBaseURL       = "https://okr-for-jira-prod.herokuapp.com"
+	defaultTimeout       = 10 * time.Second
+	objectivesByDatePath = "/api/v2/api-export/objectives/byDate?startDateEpochMilli=%d&deadlineEpochMilli=%d&expand=%s"
+	objectivesByIDsPath  = "/api/v2/api-export/objectives/byIds?objectiveIds=%s&expand=%s"
+	keyResultsByDatePath = "/api/v2/api-export/keyResults/byDate?startDateEpochMilli=%d&deadlineEpochMilli=%d&expand=%s"
+	keyResultsByIDsPath  = "/api/v2/api-export/keyResults/byIds?keyResultIds=%s&expand=%s"
+	objectiveUpdatePath  = "/api/v2/api-update/objectives"
+	keyResultUpdatePath  = "/api/v2/api-update/keyResults"
+	dateFormat           = "2006-01-02T15:04:05-0700"
 )
 
-var validObjectTypes = []string{"OBJECTIVES", "KEY_RESULTS", "TEAMS", "PERIODS", "LABELS"}
-
-func (c Client) executeGetQuery(url string) (Response, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func (c Client) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return Response{}, err
+		return nil, err
+	}
+	for key, values := range c.extraHeaders {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
 	}
 	req.Header.Set("API-Token", c.token)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/json")
-	r, err := c.httpClient.Do(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+func (c Client) executeGetQuery(ctx context.Context, url string) (Response, error) {
+	r, err := c.execute(ctx, "GET", url, "", c.retryPolicy != nil)
 	if err != nil {
 		return Response{}, err
 	}
 	defer r.Body.Close()
 
-	if r.StatusCode != 200 {
-		content, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return Response{}, err
-		}
-		return Response{}, fmt.Errorf("error status %d: %s", r.StatusCode, string(content))
-	}
 	var response Response
 
-	json := jsontime.ConfigWithCustomTimeFormat
-	jsontime.AddTimeFormatAlias("okr4j_format", dateFormat)
-	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+	if err := c.driftChecker().decode(r.Body, &response); err != nil {
 		return Response{}, err
 	}
+	if c.location != nil {
+		response = response.In(c.location)
+	}
 	return response, nil
 }
 
-func (c Client) executePostQuery(url string, body string, response interface{}) error {
-	req, err := http.NewRequest("POST", url, strings.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("API-Token", c.token)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/json")
-	r, err := c.httpClient.Do(req)
+func (c Client) executePostQuery(ctx context.Context, url string, body string, response interface{}) error {
+	r, err := c.execute(ctx, "POST", url, body, c.retryPolicy != nil && c.retryPolicy.RetryUpdates)
 	if err != nil {
 		return err
 	}
 	defer r.Body.Close()
 
-	if r.StatusCode != 200 && r.StatusCode != 201 {
-		content, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return err
-		}
-		return fmt.Errorf("error status %d: %s", r.StatusCode, string(content))
-	}
-
-	json := jsontime.ConfigWithCustomTimeFormat
-	jsontime.AddTimeFormatAlias("okr4j_format", dateFormat)
-	if err := json.NewDecoder(r.Body).Decode(response); err != nil {
+	if err := c.driftChecker().decode(r.Body, response); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (c *Client) checkObject(expand []string) error {
-	for _, object := range expand {
-		if !slices.Contains(validObjectTypes, object) {
-			return fmt.Errorf("invalid object %s", object)
+// execute sends the request, retrying it according to the retry policy if retry is true.
+// The body of the returned response must be closed by the caller.
+func (c Client) execute(ctx context.Context, method, url, body string, retry bool) (*http.Response, error) {
+	if !retry {
+		return c.send(ctx, method, url, body)
+	}
+	return c.retryPolicy.do(ctx, func() (*http.Response, error) {
+		return c.send(ctx, method, url, body)
+	})
+}
+
+// send sends a single request and returns an APIError if the status code is not a success.
+func (c Client) send(ctx context.Context, method, url, body string) (*http.Response, error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return nil, err
 		}
 	}
-	return nil
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := c.newRequest(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, transportError(ctx, err)
+	}
+	if r.StatusCode != http.StatusOK && !(method == "POST" && r.StatusCode == http.StatusCreated) {
+		defer r.Body.Close()
+		return nil, newAPIError(req, r)
+	}
+	return r, nil
+}
+
+// transportError wraps the context error when the request was aborted
+// because ctx was cancelled or its deadline expired, so that callers can use
+// errors.Is(err, context.Canceled) or errors.Is(err, context.DeadlineExceeded),
+// and keeps the transport error in the message.
+// Otherwise the original transport error is returned.
+func transportError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %v", ctxErr, err)
+	}
+	return err
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"io/ioutil"
 	"net/http"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -203,7 +204,7 @@ func TestClient_ObjectivesByDate(t *testing.T) {
 					EntityID:    "5fda249d289742000406b3e4",
 					Status:      "ON_TRACK",
 					Created:     time.Date(2021, time.May, 5, 12, 15, 14, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NullFloat64{},
 					Description: "",
 				},
 				PeriodAliasID: "602a6a2717378700039f342a",
@@ -237,7 +238,7 @@ func TestClient_ObjectivesByDate(t *testing.T) {
 					EntityID:    "620ea538512edb00acf67ac1",
 					Status:      "ON_TRACK",
 					Created:     time.Date(2022, time.February, 17, 19, 42, 43, 0, time.UTC),
-					Value:       1.0,
+					Value:       okrforjira.NewNullFloat64(1.0),
 					Description: "",
 				},
 				Unit: okrforjira.Unit{
@@ -246,8 +247,8 @@ func TestClient_ObjectivesByDate(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "AUTO",
-					StartValue:   0.0,
-					DesiredValue: 0.0,
+					StartValue:   okrforjira.NullFloat64{},
+					DesiredValue: okrforjira.NullFloat64{},
 					JQL:          "project= TEST",
 				},
 				Weight: 1.0,
@@ -274,7 +275,7 @@ func TestClient_ObjectivesByDate(t *testing.T) {
 					EntityID:    "61138f2be5fd454858c3e1ee",
 					Status:      "AT_RISK",
 					Created:     time.Date(2021, time.August, 11, 8, 49, 47, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "",
 				},
 				Unit: okrforjira.Unit{
@@ -283,8 +284,8 @@ func TestClient_ObjectivesByDate(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 1.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(1.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -663,7 +664,7 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 					EntityID:    "5fda249d289742000406b3e4",
 					Status:      "ON_TRACK",
 					Created:     time.Date(2021, time.May, 5, 12, 15, 14, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NullFloat64{},
 					Description: "",
 				},
 				PeriodAliasID: "602a6a2717378700039f342a",
@@ -694,7 +695,7 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 					EntityID:    "5fdb72c63d2cf000035ceb37",
 					Status:      "UNDEFINED",
 					Created:     time.Date(2021, time.August, 9, 0, 0, 0, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NullFloat64{},
 					Description: "",
 				},
 				PeriodAliasID: "602a6a2717378700039f342c",
@@ -724,7 +725,7 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 					EntityID:    "61c993aaa0fd9b768a0fb47d",
 					Status:      "AT_RISK",
 					Created:     time.Date(2021, time.December, 27, 10, 21, 30, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "",
 				},
 				Unit: okrforjira.Unit{
@@ -733,8 +734,8 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 22.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(22.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -761,7 +762,7 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 					EntityID:    "5fdb72c63d2cf000035ceb39",
 					Status:      "ON_TRACK",
 					Created:     time.Date(2020, time.December, 17, 15, 0, 24, 0, time.UTC),
-					Value:       1.0,
+					Value:       okrforjira.NewNullFloat64(1.0),
 					Description: "",
 				},
 				Unit: okrforjira.Unit{
@@ -770,8 +771,8 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   1.0,
-					DesiredValue: 2.0,
+					StartValue:   okrforjira.NewNullFloat64(1.0),
+					DesiredValue: okrforjira.NewNullFloat64(2.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -796,7 +797,7 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 					EntityID:    "5ff445909f8c190003e0e447",
 					Status:      "DELAYED",
 					Created:     time.Date(2021, time.January, 5, 10, 55, 12, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "",
 				},
 				Unit: okrforjira.Unit{
@@ -805,8 +806,8 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 1.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(1.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -837,7 +838,7 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 					EntityID:    "620ea538512edb00acf67ac1",
 					Status:      "ON_TRACK",
 					Created:     time.Date(2022, time.February, 17, 19, 42, 43, 0, time.UTC),
-					Value:       1.0,
+					Value:       okrforjira.NewNullFloat64(1.0),
 					Description: "",
 				},
 				Unit: okrforjira.Unit{
@@ -846,8 +847,8 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "AUTO",
-					StartValue:   0.0,
-					DesiredValue: 0.0,
+					StartValue:   okrforjira.NullFloat64{},
+					DesiredValue: okrforjira.NullFloat64{},
 					JQL:          "project= TEST",
 				},
 				Weight: 1.0,
@@ -874,7 +875,7 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 					EntityID:    "61138f2be5fd454858c3e1ee",
 					Status:      "AT_RISK",
 					Created:     time.Date(2021, time.August, 11, 8, 49, 47, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "",
 				},
 				Unit: okrforjira.Unit{
@@ -883,8 +884,8 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 1.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(1.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -914,7 +915,7 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 					EntityID:    "60bde9dc99b4177c88ad428d",
 					Status:      "ON_TRACK",
 					Created:     time.Date(2021, time.June, 7, 9, 41, 37, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "",
 				},
 				Unit: okrforjira.Unit{
@@ -923,8 +924,8 @@ func TestClient_ObjectivesByIDs(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 1.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(1.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -1354,7 +1355,7 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 					EntityID:    "620265fe81300a4c89e89806",
 					Status:      "NOT_STARTED",
 					Created:     time.Date(2022, time.February, 8, 12, 45, 50, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NullFloat64{},
 					Description: "Objective created.",
 				},
 				PeriodAliasID: "602a6a2717378700039f342f",
@@ -1385,7 +1386,7 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 					EntityID:    "6202664f81300a4c89e89810",
 					Status:      "NOT_STARTED",
 					Created:     time.Date(2022, time.February, 8, 12, 47, 11, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NullFloat64{},
 					Description: "Objective created.",
 				},
 				PeriodAliasID: "602a6a2717378700039f342f",
@@ -1417,7 +1418,7 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 					EntityID:    "6202666281300a4c89e89812",
 					Status:      "NOT_STARTED",
 					Created:     time.Date(2022, time.February, 8, 12, 47, 30, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NullFloat64{},
 					Description: "Objective created.",
 				},
 				PeriodAliasID: "602a6a2717378700039f342f",
@@ -1445,7 +1446,7 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 					EntityID:    "6202671581300a4c89e89828",
 					Status:      "NOT_STARTED",
 					Created:     time.Date(2022, time.February, 8, 12, 50, 29, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "Key result created.",
 				},
 				Unit: okrforjira.Unit{
@@ -1454,8 +1455,8 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 1.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(1.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -1480,7 +1481,7 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 					EntityID:    "6202672781300a4c89e8982b",
 					Status:      "NOT_STARTED",
 					Created:     time.Date(2022, time.February, 8, 12, 50, 47, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "Key result created.",
 				},
 				Unit: okrforjira.Unit{
@@ -1489,8 +1490,8 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 1.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(1.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -1515,7 +1516,7 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 					EntityID:    "6202673e81300a4c89e8982f",
 					Status:      "NOT_STARTED",
 					Created:     time.Date(2022, time.February, 8, 12, 51, 9, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "Key result created.",
 				},
 				Unit: okrforjira.Unit{
@@ -1524,8 +1525,8 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 1.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(1.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -1550,7 +1551,7 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 					EntityID:    "6202674e81300a4c89e89831",
 					Status:      "NOT_STARTED",
 					Created:     time.Date(2022, time.February, 8, 12, 51, 26, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "Key result created.",
 				},
 				Unit: okrforjira.Unit{
@@ -1559,8 +1560,8 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 1.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(1.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -1585,7 +1586,7 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 					EntityID:    "62027c6f81300a4c89e89a1d",
 					Status:      "NOT_STARTED",
 					Created:     time.Date(2022, time.February, 8, 14, 21, 35, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "Key result created.",
 				},
 				Unit: okrforjira.Unit{
@@ -1594,8 +1595,8 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 1.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(1.0),
 					JQL:          "",
 				},
 				Weight: 0.0,
@@ -1628,7 +1629,7 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 					EntityID:    "62029d8881300a4c89e8af2a",
 					Status:      "NOT_STARTED",
 					Created:     time.Date(2022, time.February, 8, 16, 42, 48, 0, time.UTC),
-					Value:       2.0,
+					Value:       okrforjira.NewNullFloat64(2.0),
 					Description: "",
 				},
 				Unit: okrforjira.Unit{
@@ -1637,8 +1638,8 @@ func TestClient_KeyResultsByDate(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "AUTO",
-					StartValue:   0.0,
-					DesiredValue: 0.0,
+					StartValue:   okrforjira.NullFloat64{},
+					DesiredValue: okrforjira.NullFloat64{},
 					JQL:          "project = ddd",
 				},
 				Weight: 1.0,
@@ -1802,7 +1803,7 @@ func TestClient_KeyResultsByIDs(t *testing.T) {
 					EntityID:    "5fdb72c63d2cf000035ceb37",
 					Status:      "UNDEFINED",
 					Created:     time.Date(2021, time.August, 9, 0, 0, 0, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NullFloat64{},
 					Description: "",
 				},
 				PeriodAliasID: "602a6a2717378700039f342c",
@@ -1830,7 +1831,7 @@ func TestClient_KeyResultsByIDs(t *testing.T) {
 					EntityID:    "61c993aaa0fd9b768a0fb47d",
 					Status:      "AT_RISK",
 					Created:     time.Date(2021, time.December, 27, 10, 21, 30, 0, time.UTC),
-					Value:       0.0,
+					Value:       okrforjira.NewNullFloat64(0.0),
 					Description: "",
 				},
 				Unit: okrforjira.Unit{
@@ -1839,8 +1840,8 @@ func TestClient_KeyResultsByIDs(t *testing.T) {
 				},
 				CurrentProgressDefinition: okrforjira.ProgressDefinition{
 					Type:         "STANDARD",
-					StartValue:   0.0,
-					DesiredValue: 22.0,
+					StartValue:   okrforjira.NewNullFloat64(0.0),
+					DesiredValue: okrforjira.NewNullFloat64(22.0),
 					JQL:          "",
 				},
 				Weight: 1.0,
@@ -1895,9 +1896,9 @@ func TestClient_UpdateObjective(t *testing.T) {
 
 	want := okrforjira.Update{
 		EntityID:    objectiveID,
-		Status:      status,
+		Status:      okrforjira.StatusOnTrack,
 		Created:     time.Date(2022, time.May, 20, 9, 58, 9, 0, time.UTC),
-		Value:       0.0,
+		Value:       okrforjira.NullFloat64{},
 		Description: description,
 	}
 	if !cmp.Equal(got, want) {
@@ -1939,9 +1940,9 @@ func TestClient_UpdateKeyResult(t *testing.T) {
 
 	want := okrforjira.Update{
 		EntityID:    keyResultID,
-		Status:      status,
+		Status:      okrforjira.StatusAtRisk,
 		Created:     time.Date(2022, time.May, 20, 13, 1, 35, 0, time.UTC),
-		Value:       value,
+		Value:       okrforjira.NewNullFloat64(value),
 		Description: description,
 	}
 	if !cmp.Equal(got, want) {
@@ -1949,6 +1950,94 @@ func TestClient_UpdateKeyResult(t *testing.T) {
 	}
 }
 
+func TestClient_UpdateStatusSpelling(t *testing.T) {
+	// The update endpoints spell the statuses with spaces, e.g. "ON TRACK".
+	var bodies []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		return newJSONResponse(200, `{}`)
+	})
+	ctx := context.Background()
+	c := okrforjira.NewClient(client, token)
+	_, err := c.UpdateObjective(ctx, "o1", okrforjira.StatusOnTrack, "")
+	assert.NoError(t, err)
+	_, err = c.UpdateKeyResult(ctx, "kr1", "not-started", 1, "")
+	assert.NoError(t, err)
+
+	if assert.Len(t, bodies, 2) {
+		assert.JSONEq(t, `{"objectiveId":"o1","status":"ON TRACK","description":""}`, bodies[0])
+		assert.JSONEq(t, `{"keyResultId":"kr1","status":"NOT STARTED","newValue":1,"description":""}`, bodies[1])
+	}
+}
+
+func TestClient_ContextCancellation(t *testing.T) {
+	// The fake server blocks until the request context is done,
+	// as a real transport would do for a slow server.
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		<-req.Context().Done()
+		return nil
+	})
+	c := okrforjira.NewClient(client, token)
+
+	startDate := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC)
+	calls := map[string]func(ctx context.Context) error{
+		"ObjectivesByDate": func(ctx context.Context) error {
+			_, err := c.ObjectivesByDate(ctx, startDate, deadline, nil)
+			return err
+		},
+		"ObjectivesByIDs": func(ctx context.Context) error {
+			_, err := c.ObjectivesByIDs(ctx, []string{"5fda249d289742000406b3e4"}, nil)
+			return err
+		},
+		"KeyResultsByDate": func(ctx context.Context) error {
+			_, err := c.KeyResultsByDate(ctx, startDate, deadline, nil)
+			return err
+		},
+		"KeyResultsByIDs": func(ctx context.Context) error {
+			_, err := c.KeyResultsByIDs(ctx, []string{"5fda249d289742000406b3e5"}, nil)
+			return err
+		},
+		"UpdateObjective": func(ctx context.Context) error {
+			_, err := c.UpdateObjective(ctx, "62334eac00ee2b102e34fdb7", "ON_TRACK", "")
+			return err
+		},
+		"UpdateKeyResult": func(ctx context.Context) error {
+			_, err := c.UpdateKeyResult(ctx, "62384a6942adda046598b3bd", "ON_TRACK", 1.0, "")
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		call := call
+		t.Run(name+" cancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+			err := call(ctx)
+			assert.ErrorIs(t, err, context.Canceled)
+			// The transport error is kept in the message.
+			assert.Contains(t, err.Error(), "https://okr-for-jira-prod.herokuapp.com/api/")
+		})
+		t.Run(name+" deadline exceeded", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			err := call(ctx)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+	}
+
+	t.Run("Transport errors are not reported as cancellations", func(t *testing.T) {
+		c := okrforjira.NewClient(NewTestClient(func(req *http.Request) *http.Response {
+			return nil
+		}), token)
+		_, err := c.ObjectivesByDate(context.Background(), startDate, deadline, nil)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
 // Instead of the default http.Transport we will use http.RoundTripper.
 // It will allow us to fake the server.
 
@@ -1967,3 +2056,23 @@ func NewTestClient(fn RoundTripFunc) *http.Client {
 		Transport: RoundTripFunc(fn),
 	}
 }
+
+// readFixture returns the content of a file of the testdata directory.
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// newJSONResponse returns a response with the provided status code and JSON body.
+func newJSONResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		// Must be set to non-nil value or it panics
+		Header: make(http.Header),
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"gopkg.in/yaml.v3"
+)
+
+// The formats of the bulk check-in files.
+const (
+	formatCSV  = "csv"
+	formatYAML = "yaml"
+)
+
+// bulkCheckinFlags are the flags of the bulk-checkin command.
+type bulkCheckinFlags struct {
+	clientFlags
+	searchFlags
+	format      string
+	dryRun      bool
+	concurrency int
+	output      string
+}
+
+func (f *bulkCheckinFlags) register(fs *flag.FlagSet) {
+	f.clientFlags.register(fs)
+	f.searchFlags.register(fs)
+	fs.StringVar(&f.format, "format", "", "format of the file: csv or yaml (default from the file extension)")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "validate the check-ins and show the changes without applying them")
+	fs.IntVar(&f.concurrency, "concurrency", 4, "maximum number of updates sent at a time")
+	fs.StringVar(&f.output, "output", "table", "output format of the report: table, json or yaml")
+}
+
+func runBulkCheckin(ctx context.Context, e *env, args []string) error {
+	var f bulkCheckinFlags
+	fs := newFlagSet(e, "bulk-checkin")
+	f.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkOutput(e, fs, f.output); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError(e, fs, `a single file is required, "-" to read it from stdin`)
+	}
+	if f.concurrency < 1 {
+		return usageError(e, fs, "-concurrency must be at least 1")
+	}
+	name := fs.Arg(0)
+	format, err := checkInFormat(name, f.format)
+	if err != nil {
+		return usageError(e, fs, err.Error())
+	}
+	search, err := f.searchRange(time.Now())
+	if err != nil {
+		return usageError(e, fs, err.Error())
+	}
+
+	checkIns, err := readCheckInFile(e, name, format)
+	if err != nil {
+		return err
+	}
+	c, err := f.client(e)
+	if err != nil {
+		return err
+	}
+	plan, err := c.PlanCheckIns(ctx, checkIns, search)
+	if err != nil {
+		return err
+	}
+	if err := writePlan(e.stderr, plan); err != nil {
+		return err
+	}
+	if n := plan.Invalid(); n > 0 {
+		return fmt.Errorf("%d invalid check-ins, nothing was updated", n)
+	}
+	if f.dryRun {
+		fmt.Fprintf(e.stderr, "\nDry run: %d check-ins were not applied.\n", len(plan.CheckIns))
+		return nil
+	}
+
+	results, err := c.ApplyCheckIns(ctx, plan, f.concurrency)
+	if err != nil {
+		return err
+	}
+	if err := writeResults(e.stdout, f.output, results); err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.ApplyErr != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d check-ins failed", failed, len(results))
+	}
+	return nil
+}
+
+// checkInFormat returns the format of the file, from the -format flag or
+// from the file extension.
+func checkInFormat(name, format string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".csv":
+			format = formatCSV
+		case ".yaml", ".yml":
+			format = formatYAML
+		default:
+			return "", fmt.Errorf("cannot guess the format of %q, use -format", name)
+		}
+	}
+	switch format {
+	case formatCSV, formatYAML:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid file format %q", format)
+}
+
+// checkInRow is a row of a bulk check-in file. The entity is referenced by
+// one of key, id or ref, and the value is either value or newValue.
+type checkInRow struct {
+	Key         string   `yaml:"key"`
+	ID          string   `yaml:"id"`
+	Ref         string   `yaml:"ref"`
+	Status      string   `yaml:"status"`
+	Value       *float64 `yaml:"value"`
+	NewValue    *float64 `yaml:"newValue"`
+	Description string   `yaml:"description"`
+
+	// line is the line of the row in the file.
+	line int
+}
+
+// checkIn returns the check-in of the row, or an error if the row sets
+// several references or values.
+func (r checkInRow) checkIn() (okrforjira.CheckIn, error) {
+	refs := 0
+	for _, ref := range []string{r.Key, r.ID, r.Ref} {
+		if ref != "" {
+			refs++
+		}
+	}
+	if refs > 1 {
+		return okrforjira.CheckIn{}, errors.New("only one of key, id and ref can be set")
+	}
+	if r.Value != nil && r.NewValue != nil {
+		return okrforjira.CheckIn{}, errors.New("only one of value and newValue can be set")
+	}
+	checkIn := okrforjira.CheckIn{
+		Ref:         firstNonEmpty(r.Key, r.ID, r.Ref),
+		Status:      okrforjira.Status(r.Status),
+		Description: r.Description,
+	}
+	if r.Value != nil {
+		checkIn.Value = okrforjira.NewNullFloat64(*r.Value)
+	} else if r.NewValue != nil {
+		checkIn.Value = okrforjira.NewNullFloat64(*r.NewValue)
+	}
+	return checkIn, nil
+}
+
+// readCheckInFile reads the check-ins of the file, or of stdin if name is "-".
+func readCheckInFile(e *env, name, format string) ([]okrforjira.CheckIn, error) {
+	r := e.stdin
+	if name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+	var rows []checkInRow
+	var err error
+	if format == formatCSV {
+		rows, err = readCheckInCSV(r)
+	} else {
+		rows, err = readCheckInYAML(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no check-ins in %s", name)
+	}
+	checkIns := make([]okrforjira.CheckIn, len(rows))
+	for i, row := range rows {
+		if checkIns[i], err = row.checkIn(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: line %d: %w", name, row.line, err)
+		}
+	}
+	return checkIns, nil
+}
+
+// readCheckInYAML reads a YAML list of check-ins.
+func readCheckInYAML(r io.Reader) ([]checkInRow, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var rows []checkInRow
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&rows); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	// The lines of the rows are those of the items of the list.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 1 && len(doc.Content[0].Content) == len(rows) {
+		for i, item := range doc.Content[0].Content {
+			rows[i].line = item.Line
+		}
+	}
+	return rows, nil
+}
+
+// readCheckInCSV reads a CSV file of check-ins. The first line is the header
+// naming the columns, which are the fields of the YAML format.
+func readCheckInCSV(r io.Reader) ([]checkInRow, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	header := records[0]
+	for _, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "key", "id", "ref", "status", "value", "newvalue", "description":
+		default:
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+	rows := make([]checkInRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		row := checkInRow{line: i + 2}
+		for j, field := range record {
+			field = strings.TrimSpace(field)
+			switch column := strings.ToLower(strings.TrimSpace(header[j])); column {
+			case "key":
+				row.Key = field
+			case "id":
+				row.ID = field
+			case "ref":
+				row.Ref = field
+			case "status":
+				row.Status = field
+			case "value", "newvalue":
+				if field == "" {
+					continue
+				}
+				v, err := strconv.ParseFloat(field, 64)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid value %q", i+2, field)
+				}
+				if column == "value" {
+					row.Value = &v
+				} else {
+					row.NewValue = &v
+				}
+			case "description":
+				row.Description = field
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// writePlan writes the changes of the check-ins compared to the latest
+// updates, and the validation errors.
+func writePlan(w io.Writer, plan *okrforjira.CheckInPlan) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROW\tKEY\tNAME\tSTATUS\tVALUE\tDESCRIPTION")
+	for i, c := range plan.CheckIns {
+		if c.Err != nil {
+			fmt.Fprintf(tw, "%d\t%s\tERROR: %s\t\t\t\n", i+1, c.Ref, c.Err)
+			continue
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", i+1, c.Key, c.Name,
+			formatChange(formatStatus(c.Previous.Status), formatStatus(c.Status)),
+			formatChange(formatValue(c.Previous.Value, okrforjira.Unit{}), formatValue(c.Value, okrforjira.Unit{})),
+			c.Description)
+	}
+	return tw.Flush()
+}
+
+// formatChange returns "old -> new", or only the value if it is unchanged.
+func formatChange(before, after string) string {
+	if before == after {
+		return after
+	}
+	return before + " -> " + after
+}
+
+// checkInReport is a row of the report of the bulk-checkin command.
+type checkInReport struct {
+	Row    int                `json:"row"`
+	Key    string             `json:"key"`
+	ID     string             `json:"id"`
+	Update *okrforjira.Update `json:"update,omitempty"`
+	Error  string             `json:"error,omitempty"`
+}
+
+// writeResults writes the report of the applied check-ins in the output format.
+func writeResults(w io.Writer, format string, results []okrforjira.CheckInResult) error {
+	reports := make([]checkInReport, len(results))
+	for i, r := range results {
+		reports[i] = checkInReport{Row: i + 1, Key: r.Key, ID: r.ID}
+		if r.ApplyErr != nil {
+			reports[i].Error = r.ApplyErr.Error()
+		} else {
+			update := r.Update
+			reports[i].Update = &update
+		}
+	}
+	switch format {
+	case outputJSON:
+		return writeJSON(w, reports)
+	case outputYAML:
+		return writeYAML(w, reports)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROW\tKEY\tRESULT")
+	for _, r := range reports {
+		result := "OK"
+		if r.Error != "" {
+			result = "FAILED: " + r.Error
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", r.Row, r.Key, result)
+	}
+	return tw.Flush()
+}
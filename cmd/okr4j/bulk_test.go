@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grandper/okrforjira"
+	"github.com/grandper/okrforjira/okrforjiratest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_BulkCheckin(t *testing.T) {
+	search := []string{"-search-start", "2022-01-01", "-search-deadline", "2022-12-31"}
+	bulk := func(vars map[string]string, stdin string, args ...string) (int, string, string) {
+		return runTest(t, vars, stdin, append(append([]string{"bulk-checkin"}, search...), args...)...)
+	}
+	writeFile := func(name, content string) string {
+		path := filepath.Join(t.TempDir(), name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	updates := func(s *okrforjiratest.Server) int {
+		n := 0
+		for _, r := range s.Requests() {
+			if r.Method == http.MethodPost {
+				n++
+			}
+		}
+		return n
+	}
+
+	csvFile := writeFile("checkins.csv", "key,status,newValue,description\n"+
+		"KR-1,at risk,4500,Slower growth\n"+
+		"kr3,ON_TRACK,12,\"Fewer incidents, finally\"\n"+
+		"O-2,DELAYED,,Blocked\n")
+
+	t.Run("Dry run", func(t *testing.T) {
+		s, vars := newTestServer(t)
+		code, stdout, stderr := bulk(vars, "", "-dry-run", csvFile)
+		assert.Equal(t, 0, code, stderr)
+		assert.Empty(t, stdout)
+		assert.Contains(t, stderr, "ON TRACK -> AT RISK  4000 -> 4500  Slower growth\n")
+		assert.Contains(t, stderr, "KR-3  Reduce the number of incidents")
+		assert.Contains(t, stderr, "Fewer incidents, finally\n")
+		assert.Contains(t, stderr, "Dry run: 3 check-ins were not applied.\n")
+		assert.Equal(t, 0, updates(s))
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		s, vars := newTestServer(t)
+		code, stdout, stderr := bulk(vars, "", "-concurrency", "2", csvFile)
+		assert.Equal(t, 0, code, stderr)
+		assert.Equal(t, "ROW  KEY   RESULT\n1    KR-1  OK\n2    KR-3  OK\n3    O-2   OK\n", stdout)
+		assert.Equal(t, 3, updates(s))
+
+		idx := okrforjira.NewIndex(s.Response())
+		kr1, _ := idx.KeyResult("kr1")
+		assert.Equal(t, okrforjira.StatusAtRisk, kr1.LatestUpdate.Status)
+		assert.Equal(t, okrforjira.NewNullFloat64(4500), kr1.LatestUpdate.Value)
+		o2, _ := idx.OKR("o2")
+		assert.Equal(t, "Blocked", o2.LatestUpdate.Description)
+	})
+
+	t.Run("YAML from stdin", func(t *testing.T) {
+		s, vars := newTestServer(t)
+		yamlFile := "- key: KR-4\n  status: on track\n  value: 35\n  description: Better onboarding\n- id: o1\n  status: AT_RISK\n  description: Slower growth\n"
+		code, stdout, stderr := bulk(vars, yamlFile, "-format", "yaml", "-output", "json", "-")
+		assert.Equal(t, 0, code, stderr)
+
+		var got []checkInReport
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &got))
+		if assert.Len(t, got, 2) {
+			assert.Equal(t, "KR-4", got[0].Key)
+			assert.Equal(t, okrforjira.NewNullFloat64(35), got[0].Update.Value)
+			assert.Equal(t, "O-1", got[1].Key)
+			assert.Equal(t, okrforjira.StatusAtRisk, got[1].Update.Status)
+		}
+		assert.Equal(t, 2, updates(s))
+	})
+
+	t.Run("Invalid check-ins", func(t *testing.T) {
+		s, vars := newTestServer(t)
+		file := writeFile("checkins.yml", "- key: KR-1\n  status: ON_TRACK\n- key: KR-9\n  status: ON_TRACK\n  value: 1\n- key: O-1\n  status: ON_TRACK\n  description: Fine\n")
+		code, _, stderr := bulk(vars, "", file)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "ERROR: missing value of key result KR-1")
+		assert.Contains(t, stderr, "ERROR: KR-9: okrforjira: not found")
+		assert.Contains(t, stderr, "2 invalid check-ins, nothing was updated")
+		assert.Equal(t, 0, updates(s))
+	})
+
+	t.Run("Failed update", func(t *testing.T) {
+		s, vars := newTestServer(t)
+		s.InjectFault(okrforjiratest.Fault{
+			Method:     http.MethodPost,
+			Path:       okrforjiratest.ObjectiveUpdatePath,
+			StatusCode: http.StatusBadRequest,
+			Body:       `{"message":"rejected"}`,
+		})
+		code, stdout, stderr := bulk(vars, "", csvFile)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stdout, "2    KR-3  OK\n")
+		assert.Contains(t, stdout, "3    O-2   FAILED: ")
+		assert.Contains(t, stderr, "1 of 3 check-ins failed")
+	})
+
+	t.Run("Invalid files", func(t *testing.T) {
+		_, vars := newTestServer(t)
+		code, _, stderr := bulk(vars, "", writeFile("checkins.txt", ""))
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, "use -format")
+
+		code, _, stderr = bulk(vars, "", writeFile("checkins.csv", "key,state\nKR-1,ON_TRACK\n"))
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, `unknown column "state"`)
+
+		code, _, stderr = bulk(vars, "", writeFile("checkins.csv", "key,status,value\nKR-1,ON_TRACK,many\n"))
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, `line 2: invalid value "many"`)
+
+		code, _, stderr = bulk(vars, "", writeFile("checkins.csv", "key,status,value,newValue\nKR-1,ON_TRACK,1,\nKR-4,ON_TRACK,2,3\n"))
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "line 3: only one of value and newValue can be set")
+
+		code, _, stderr = bulk(vars, "", writeFile("checkins.csv", "key,id,status,value\nKR-1,kr4,ON_TRACK,1\n"))
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "line 2: only one of key, id and ref can be set")
+
+		code, _, stderr = bulk(vars, "", writeFile("checkins.yaml", "- key: KR-1\n  status: ON_TRACK\n  value: 1\n\n- ref: KR-4\n  id: kr4\n  status: ON_TRACK\n  value: 2\n"))
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "line 5: only one of key, id and ref can be set")
+
+		code, _, stderr = bulk(vars, "", writeFile("checkins.yaml", "- key: KR-1\n  status: ON_TRACK\n  value: 1\n  newValue: 2\n"))
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "line 1: only one of value and newValue can be set")
+
+		code, _, stderr = bulk(vars, "", writeFile("checkins.yaml", "- key: KR-1\n  state: ON_TRACK\n"))
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "field state not found")
+
+		code, _, stderr = bulk(vars, "", writeFile("checkins.yaml", ""))
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "no check-ins in")
+	})
+}
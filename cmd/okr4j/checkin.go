@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grandper/okrforjira"
+)
+
+// searchFlags are the flags of the range of dates in which keys are looked up.
+type searchFlags struct {
+	searchStart    string
+	searchDeadline string
+}
+
+func (f *searchFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.searchStart, "search-start", "", "start of the range in which keys are looked up (default one year ago)")
+	fs.StringVar(&f.searchDeadline, "search-deadline", "", "end of the range in which keys are looked up (default in one year)")
+}
+
+// searchRange returns the range of dates in which keys are looked up.
+func (f *searchFlags) searchRange(now time.Time) (okrforjira.DateRange, error) {
+	r := okrforjira.DateRange{StartDate: now.AddDate(-1, 0, 0), Deadline: now.AddDate(1, 0, 0)}
+	if f.searchStart != "" {
+		start, err := parseDate(f.searchStart, false)
+		if err != nil {
+			return okrforjira.DateRange{}, err
+		}
+		r.StartDate = start
+	}
+	if f.searchDeadline != "" {
+		deadline, err := parseDate(f.searchDeadline, true)
+		if err != nil {
+			return okrforjira.DateRange{}, err
+		}
+		r.Deadline = deadline
+	}
+	return r, nil
+}
+
+// checkinFlags are the flags of the checkin command.
+type checkinFlags struct {
+	clientFlags
+	searchFlags
+	status      string
+	value       string
+	description string
+	output      string
+}
+
+func (f *checkinFlags) register(fs *flag.FlagSet) {
+	f.clientFlags.register(fs)
+	f.searchFlags.register(fs)
+	fs.StringVar(&f.status, "status", "", "new status: ON_TRACK, AT_RISK, DELAYED, NOT_STARTED or UNDEFINED (required)")
+	fs.StringVar(&f.value, "value", "", "new value of a key result (required for a key result)")
+	fs.StringVar(&f.description, "description", "", `description of the check-in, "-" to read it from stdin (default: edit it with $EDITOR)`)
+	fs.StringVar(&f.output, "output", "table", "output format: table, json or yaml")
+}
+
+func runCheckin(ctx context.Context, e *env, args []string) error {
+	var f checkinFlags
+	fs := newFlagSet(e, "checkin")
+	f.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkOutput(e, fs, f.output); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError(e, fs, "a single key or ID is required")
+	}
+	ref := fs.Arg(0)
+	if f.status == "" {
+		return usageError(e, fs, "-status is required")
+	}
+	status, err := okrforjira.ParseStatus(f.status)
+	if err != nil {
+		return usageError(e, fs, err.Error())
+	}
+	var value *float64
+	if f.value != "" {
+		v, err := strconv.ParseFloat(f.value, 64)
+		if err != nil {
+			return usageError(e, fs, fmt.Sprintf("invalid value %q", f.value))
+		}
+		value = &v
+	}
+	search, err := f.searchRange(time.Now())
+	if err != nil {
+		return usageError(e, fs, err.Error())
+	}
+
+	c, err := f.client(e)
+	if err != nil {
+		return err
+	}
+	found, err := c.Lookup(ctx, []string{ref}, search)
+	if err != nil {
+		return err
+	}
+	idx := okrforjira.NewIndex(found)
+	kr, isKeyResult := idx.LookupKeyResult(ref)
+	okr, isObjective := idx.LookupOKR(ref)
+	switch {
+	case isKeyResult && value == nil:
+		return usageError(e, fs, "-value is required for a key result")
+	case isObjective && value != nil:
+		return usageError(e, fs, "-value cannot be used for an objective")
+	case !isKeyResult && !isObjective:
+		return fmt.Errorf("%s: %w", ref, okrforjira.ErrNotFound)
+	}
+
+	var key, name string
+	var previous okrforjira.Update
+	if isKeyResult {
+		key, name, previous = kr.Key, kr.Name, kr.LatestUpdate
+	} else {
+		key, name, previous = okr.Key, okr.Name, okr.LatestUpdate
+	}
+	fmt.Fprintf(e.stderr, "Previous update of %s %s:\n", key, name)
+	if err := writeUpdate(e.stderr, outputTable, previous); err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stderr)
+
+	description, err := f.readDescription(ctx, e, fs, key, name, previous)
+	if err != nil {
+		return err
+	}
+
+	var update okrforjira.Update
+	if isKeyResult {
+		update, err = c.UpdateKeyResult(ctx, kr.ID, status, *value, description)
+	} else {
+		update, err = c.UpdateObjective(ctx, okr.ID, status, description)
+	}
+	if err != nil {
+		return err
+	}
+	return writeUpdate(e.stdout, f.output, update)
+}
+
+// readDescription returns the description of the -description flag, read
+// from stdin if it is "-", or edited with $VISUAL or $EDITOR if it is not set.
+func (f *checkinFlags) readDescription(ctx context.Context, e *env, fs *flag.FlagSet, key, name string, previous okrforjira.Update) (string, error) {
+	set := false
+	fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == "description" {
+			set = true
+		}
+	})
+	switch {
+	case set && f.description == "-":
+		data, err := ioutil.ReadAll(e.stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the description: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	case set:
+		return f.description, nil
+	}
+
+	var editor []string
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor = strings.Fields(e.getenv(name)); len(editor) > 0 {
+			break
+		}
+	}
+	if len(editor) == 0 {
+		return "", errors.New(`missing description: use -description, "-description -" to read it from stdin, or set $EDITOR`)
+	}
+	template := fmt.Sprintf("\n# Description of the check-in of %s %s.\n# Previous description: %s\n# The lines starting with # are ignored.\n", key, name, previous.Description)
+	return editDescription(ctx, e, editor, template)
+}
+
+// editDescription opens template in editor, the command and its arguments,
+// and returns the edited text without the comment lines.
+func editDescription(ctx context.Context, e *env, editor []string, template string) (string, error) {
+	file, err := ioutil.TempFile("", "okr4j-checkin-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(template); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, editor[0], append(editor[1:], file.Name())...)
+	cmd.Stdin = e.stdin
+	cmd.Stdout = e.stderr
+	cmd.Stderr = e.stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run the editor: %w", err)
+	}
+
+	edited, err := os.Open(file.Name())
+	if err != nil {
+		return "", err
+	}
+	defer edited.Close()
+	var lines []string
+	scanner := bufio.NewScanner(edited)
+	for scanner.Scan() {
+		if !strings.HasPrefix(scanner.Text(), "#") {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	description := strings.TrimSpace(strings.Join(lines, "\n"))
+	if description == "" {
+		return "", errors.New("empty description, check-in aborted")
+	}
+	return description, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_Checkin(t *testing.T) {
+	s, vars := newTestServer(t)
+	search := []string{"-search-start", "2022-01-01", "-search-deadline", "2022-12-31"}
+	checkin := func(vars map[string]string, stdin string, args ...string) (int, string, string) {
+		return runTest(t, vars, stdin, append(append([]string{"checkin"}, search...), args...)...)
+	}
+
+	t.Run("Key result by key", func(t *testing.T) {
+		code, stdout, stderr := checkin(vars, "", "-status", "at risk", "-value", "4500", "-description", "Slower growth", "-output", "json", "KR-1")
+		assert.Equal(t, 0, code, stderr)
+		assert.Contains(t, stderr, "Previous update of KR-1 Reach 10000 active users:\n")
+		assert.Contains(t, stderr, "Status:       ON TRACK\nValue:        4000\n")
+
+		var got okrforjira.Update
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &got))
+		assert.Equal(t, "kr1", got.EntityID)
+		assert.Equal(t, okrforjira.StatusAtRisk, got.Status)
+		assert.Equal(t, okrforjira.NewNullFloat64(4500), got.Value)
+		assert.Equal(t, "Slower growth", got.Description)
+		assert.Equal(t, okrforjira.StatusAtRisk, s.Response().KeyResults[0].LatestUpdate.Status)
+	})
+
+	t.Run("Objective by ID with description from stdin", func(t *testing.T) {
+		code, stdout, stderr := checkin(vars, "Blocked by the new cluster\n", "-status", "DELAYED", "-description", "-", "o2")
+		assert.Equal(t, 0, code, stderr)
+		assert.Contains(t, stdout, "Status:       DELAYED\n")
+		assert.Contains(t, stdout, "Description:  Blocked by the new cluster\n")
+		assert.Equal(t, "Blocked by the new cluster", s.Response().OKRs[1].LatestUpdate.Description)
+	})
+
+	t.Run("Description from the editor", func(t *testing.T) {
+		editor := filepath.Join(t.TempDir(), "editor.sh")
+		script := "#!/bin/sh\nprintf 'Referral program launched\\n' > \"$1.new\"\ncat \"$1\" >> \"$1.new\"\nmv \"$1.new\" \"$1\"\n"
+		assert.NoError(t, os.WriteFile(editor, []byte(script), 0o755))
+		withEditor := map[string]string{tokenEnv: vars[tokenEnv], configEnv: vars[configEnv], "EDITOR": editor}
+
+		code, _, stderr := checkin(withEditor, "", "-status", "on_track", "-value", "3", "KR-2")
+		assert.Equal(t, 0, code, stderr)
+		assert.Equal(t, "Referral program launched", s.Response().KeyResults[1].LatestUpdate.Description)
+	})
+
+	t.Run("Editor reading stdin", func(t *testing.T) {
+		editor := filepath.Join(t.TempDir(), "editor.sh")
+		assert.NoError(t, os.WriteFile(editor, []byte("#!/bin/sh\ncat > \"$1\"\n"), 0o755))
+		withEditor := map[string]string{tokenEnv: vars[tokenEnv], configEnv: vars[configEnv], "VISUAL": "  ", "EDITOR": editor}
+
+		code, _, stderr := checkin(withEditor, "Typed in the editor\n", "-status", "on_track", "-value", "4", "KR-2")
+		assert.Equal(t, 0, code, stderr)
+		assert.Equal(t, "Typed in the editor", s.Response().KeyResults[1].LatestUpdate.Description)
+	})
+
+	t.Run("Blank editor", func(t *testing.T) {
+		withEditor := map[string]string{tokenEnv: vars[tokenEnv], configEnv: vars[configEnv], "VISUAL": " ", "EDITOR": "\t"}
+		code, _, stderr := checkin(withEditor, "", "-status", "on_track", "-value", "3", "KR-2")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "missing description")
+	})
+
+	t.Run("Missing description", func(t *testing.T) {
+		code, _, stderr := checkin(vars, "", "-status", "on_track", "-value", "3", "KR-2")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "missing description")
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		code, _, stderr := checkin(vars, "", "-status", "done", "-description", "", "KR-2")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, `unknown status "done"`)
+	})
+
+	t.Run("Missing value", func(t *testing.T) {
+		code, _, stderr := checkin(vars, "", "-status", "on_track", "-description", "", "KR-2")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, "-value is required for a key result")
+	})
+
+	t.Run("Value of an objective", func(t *testing.T) {
+		code, _, stderr := checkin(vars, "", "-status", "on_track", "-value", "1", "-description", "", "O-1")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, "-value cannot be used for an objective")
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		code, _, stderr := checkin(vars, "", "-status", "on_track", "-description", "", "KR-99")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "KR-99: okrforjira: not found")
+	})
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read by okr4j.
+const (
+	tokenEnv   = "OKR4J_TOKEN"
+	configEnv  = "OKR4J_CONFIG"
+	baseURLEnv = "OKR4J_BASE_URL"
+)
+
+// config is the content of the configuration file, by default
+// $XDG_CONFIG_HOME/okr4j/config.yaml:
+//
+//	token: <API token>
+//	base_url: https://okr-for-jira-prod.herokuapp.com
+type config struct {
+	Token   string `yaml:"token"`
+	BaseURL string `yaml:"base_url"`
+}
+
+// clientFlags are the flags configuring the client, shared by all the commands.
+type clientFlags struct {
+	token   string
+	config  string
+	baseURL string
+	timeout time.Duration
+}
+
+func (f *clientFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.token, "token", "", "API token (default $"+tokenEnv+" or the token of the config file)")
+	fs.StringVar(&f.config, "config", "", "config file (default $"+configEnv+" or $XDG_CONFIG_HOME/okr4j/config.yaml)")
+	fs.StringVar(&f.baseURL, "base-url", "", "base URL of the API (default $"+baseURLEnv+" or the URL of the config file)")
+	fs.DurationVar(&f.timeout, "timeout", 30*time.Second, "timeout of each request")
+}
+
+// client returns a client configured from the flags, the environment
+// variables and the config file, in this order of precedence.
+func (f *clientFlags) client(e *env) (*okrforjira.Client, error) {
+	cfg, err := f.loadConfig(e)
+	if err != nil {
+		return nil, err
+	}
+	token := firstNonEmpty(f.token, e.getenv(tokenEnv), cfg.Token)
+	if token == "" {
+		return nil, fmt.Errorf("missing API token: use -token, $%s or the config file", tokenEnv)
+	}
+	opts := []okrforjira.Option{
+		okrforjira.WithTimeout(f.timeout),
+		okrforjira.WithUserAgent("okr4j"),
+	}
+	if baseURL := firstNonEmpty(f.baseURL, e.getenv(baseURLEnv), cfg.BaseURL); baseURL != "" {
+		opts = append(opts, okrforjira.WithBaseURL(baseURL))
+	}
+	return okrforjira.NewClient(nil, token, opts...), nil
+}
+
+// loadConfig reads the config file. The default config file is optional.
+func (f *clientFlags) loadConfig(e *env) (config, error) {
+	path := firstNonEmpty(f.config, e.getenv(configEnv))
+	optional := path == ""
+	if optional {
+		dir := configDir(e)
+		if dir == "" {
+			return config{}, nil
+		}
+		path = filepath.Join(dir, "okr4j", "config.yaml")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			return config{}, nil
+		}
+		return config{}, fmt.Errorf("failed to read the config file: %w", err)
+	}
+	var cfg config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return config{}, fmt.Errorf("failed to read the config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// configDir returns the directory of the user config files: $XDG_CONFIG_HOME
+// if it is an absolute path, $HOME/.config otherwise, or "" if HOME is not set.
+func configDir(e *env) string {
+	if dir := e.getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return dir
+	}
+	if home := e.getenv("HOME"); home != "" {
+		return filepath.Join(home, ".config")
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
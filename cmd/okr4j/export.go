@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/grandper/okrforjira/okrexport"
+)
+
+// stringList is a flag that can be repeated and holds comma separated values.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+// exportFlags are the flags of the export commands.
+type exportFlags struct {
+	clientFlags
+	expand     stringList
+	expandAll  bool
+	output     string
+	columnList stringList
+	columns    []okrexport.Column
+}
+
+func (f *exportFlags) register(fs *flag.FlagSet) {
+	f.clientFlags.register(fs)
+	fs.Var(&f.expand, "expand", "objects to expand: OBJECTIVES, KEY_RESULTS, TEAMS, PERIODS, LABELS (comma separated or repeated)")
+	fs.BoolVar(&f.expandAll, "expand-all", false, "expand all the objects")
+	fs.StringVar(&f.output, "output", "table", "output format: table, json, yaml, csv or xlsx")
+	fs.Var(&f.columnList, "columns", "columns of the csv and xlsx outputs, e.g. key,name,status (comma separated or repeated, default: "+defaultColumns()+")")
+}
+
+func defaultColumns() string {
+	var names []string
+	for _, column := range okrexport.DefaultColumns() {
+		names = append(names, column.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// check checks the output format and parses the columns.
+func (f *exportFlags) check(e *env, fs *flag.FlagSet) error {
+	switch f.output {
+	case outputCSV, outputXLSX:
+	default:
+		if err := checkOutput(e, fs, f.output); err != nil {
+			return err
+		}
+		if len(f.columnList) > 0 {
+			return usageError(e, fs, "-columns requires the csv or xlsx output")
+		}
+		return nil
+	}
+	columns, err := okrexport.ParseColumns(f.columnList)
+	if err != nil {
+		return usageError(e, fs, err.Error())
+	}
+	f.columns = columns
+	return nil
+}
+
+// expandObjects returns the objects to expand. With the table output, the
+// teams and periods are always expanded to show their names, and with the
+// csv and xlsx outputs, the labels too.
+func (f *exportFlags) expandObjects() ([]okrforjira.ExpandObject, error) {
+	if f.expandAll {
+		return okrforjira.ExpandAll(), nil
+	}
+	var expand []okrforjira.ExpandObject
+	for _, v := range f.expand {
+		object := okrforjira.ExpandObject(strings.ToUpper(v))
+		if err := object.Validate(); err != nil {
+			return nil, err
+		}
+		expand = append(expand, object)
+	}
+	switch f.output {
+	case outputTable:
+		expand = append(expand, okrforjira.ExpandTeams, okrforjira.ExpandPeriods)
+	case outputCSV, outputXLSX:
+		expand = append(expand, okrforjira.ExpandTeams, okrforjira.ExpandPeriods, okrforjira.ExpandLabels)
+	}
+	return okrforjira.DedupExpand(expand), nil
+}
+
+// dateFlags are the flags of the export commands by date.
+type dateFlags struct {
+	exportFlags
+	start    string
+	deadline string
+}
+
+func (f *dateFlags) register(fs *flag.FlagSet) {
+	f.exportFlags.register(fs)
+	fs.StringVar(&f.start, "start", "", "start of the range, e.g. 2022-01-01 (required)")
+	fs.StringVar(&f.deadline, "deadline", "", "end of the range, e.g. 2022-03-31 (required)")
+}
+
+// parse parses the flags and returns the range of dates.
+func (f *dateFlags) parse(e *env, fs *flag.FlagSet, args []string) (time.Time, time.Time, error) {
+	if err := parseFlags(fs, args); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if err := f.check(e, fs); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if f.start == "" || f.deadline == "" || fs.NArg() > 0 {
+		return time.Time{}, time.Time{}, usageError(e, fs, "-start and -deadline are required")
+	}
+	start, err := parseDate(f.start, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, usageError(e, fs, err.Error())
+	}
+	deadline, err := parseDate(f.deadline, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, usageError(e, fs, err.Error())
+	}
+	return start, deadline, nil
+}
+
+// parseDate parses a date like 2022-01-31, or a time in one of the formats
+// accepted by okrforjira.ParseTime. A date is the start of the day in UTC,
+// or its end if endOfDay is true.
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		if endOfDay {
+			return date.Add(24*time.Hour - time.Millisecond), nil
+		}
+		return date, nil
+	}
+	return okrforjira.ParseTime(value)
+}
+
+// idsFlags are the flags of the export commands by IDs.
+type idsFlags struct {
+	exportFlags
+}
+
+// parse parses the flags and returns the IDs of the arguments.
+func (f *idsFlags) parse(e *env, fs *flag.FlagSet, args []string) ([]string, error) {
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if err := f.check(e, fs); err != nil {
+		return nil, err
+	}
+	var ids stringList
+	for _, arg := range fs.Args() {
+		_ = ids.Set(arg)
+	}
+	if len(ids) == 0 {
+		return nil, usageError(e, fs, "at least one ID is required")
+	}
+	return ids, nil
+}
+
+func runObjectivesByDate(ctx context.Context, e *env, args []string) error {
+	var f dateFlags
+	fs := newFlagSet(e, "objectives-by-date")
+	f.register(fs)
+	start, deadline, err := f.parse(e, fs, args)
+	if err != nil {
+		return err
+	}
+	return export(ctx, e, &f.exportFlags, func(c *okrforjira.Client, expand []okrforjira.ExpandObject) (okrforjira.Response, error) {
+		return c.ObjectivesByDateExpand(ctx, start, deadline, expand...)
+	})
+}
+
+func runObjectivesByIDs(ctx context.Context, e *env, args []string) error {
+	var f idsFlags
+	fs := newFlagSet(e, "objectives-by-ids")
+	f.register(fs)
+	ids, err := f.parse(e, fs, args)
+	if err != nil {
+		return err
+	}
+	return export(ctx, e, &f.exportFlags, func(c *okrforjira.Client, expand []okrforjira.ExpandObject) (okrforjira.Response, error) {
+		return c.ObjectivesByIDsExpand(ctx, ids, expand...)
+	})
+}
+
+func runKeyResultsByDate(ctx context.Context, e *env, args []string) error {
+	var f dateFlags
+	fs := newFlagSet(e, "key-results-by-date")
+	f.register(fs)
+	start, deadline, err := f.parse(e, fs, args)
+	if err != nil {
+		return err
+	}
+	return export(ctx, e, &f.exportFlags, func(c *okrforjira.Client, expand []okrforjira.ExpandObject) (okrforjira.Response, error) {
+		return c.KeyResultsByDateExpand(ctx, start, deadline, expand...)
+	})
+}
+
+func runKeyResultsByIDs(ctx context.Context, e *env, args []string) error {
+	var f idsFlags
+	fs := newFlagSet(e, "key-results-by-ids")
+	f.register(fs)
+	ids, err := f.parse(e, fs, args)
+	if err != nil {
+		return err
+	}
+	return export(ctx, e, &f.exportFlags, func(c *okrforjira.Client, expand []okrforjira.ExpandObject) (okrforjira.Response, error) {
+		return c.KeyResultsByIDsExpand(ctx, ids, expand...)
+	})
+}
+
+// export fetches a response with fetch and writes it in the output format.
+func export(ctx context.Context, e *env, f *exportFlags, fetch func(c *okrforjira.Client, expand []okrforjira.ExpandObject) (okrforjira.Response, error)) error {
+	expand, err := f.expandObjects()
+	if err != nil {
+		return err
+	}
+	c, err := f.client(e)
+	if err != nil {
+		return err
+	}
+	response, err := fetch(c, expand)
+	if err != nil {
+		return err
+	}
+	switch f.output {
+	case outputCSV:
+		return okrexport.WriteCSV(e.stdout, response, f.columns)
+	case outputXLSX:
+		return okrexport.WriteXLSX(e.stdout, response, f.columns)
+	}
+	return writeResponse(e.stdout, f.output, response)
+}
+
+func checkOutput(e *env, fs *flag.FlagSet, output string) error {
+	switch output {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	}
+	return usageError(e, fs, fmt.Sprintf("invalid output format %q", output))
+}
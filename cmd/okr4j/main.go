@@ -0,0 +1,164 @@
+// Command okr4j queries and updates the OKRs of OKR for Jira.
+//
+// Usage:
+//
+//	okr4j <command> [flags] [arguments]
+//
+// Run "okr4j help" for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+)
+
+// env is the environment of a command, replaced in tests.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+// command is a subcommand of okr4j.
+type command struct {
+	name        string
+	args        string
+	description string
+	run         func(ctx context.Context, e *env, args []string) error
+}
+
+func commands() []command {
+	return []command{
+		{
+			name:        "objectives-by-date",
+			args:        "-start DATE -deadline DATE",
+			description: "List the objectives with a start date or deadline in the range.",
+			run:         runObjectivesByDate,
+		},
+		{
+			name:        "objectives-by-ids",
+			args:        "ID...",
+			description: "List the objectives with the given IDs.",
+			run:         runObjectivesByIDs,
+		},
+		{
+			name:        "key-results-by-date",
+			args:        "-start DATE -deadline DATE",
+			description: "List the key results with a start date or deadline in the range.",
+			run:         runKeyResultsByDate,
+		},
+		{
+			name:        "key-results-by-ids",
+			args:        "ID...",
+			description: "List the key results with the given IDs.",
+			run:         runKeyResultsByIDs,
+		},
+		{
+			name:        "checkin",
+			args:        "-status STATUS [-value VALUE] [-description TEXT] KEY|ID",
+			description: "Check in an objective or a key result by key, e.g. KR-8, or by ID.",
+			run:         runCheckin,
+		},
+		{
+			name:        "bulk-checkin",
+			args:        "[-dry-run] FILE",
+			description: "Check in the objectives and key results of a CSV or YAML file.",
+			run:         runBulkCheckin,
+		},
+	}
+}
+
+// errUsage is returned when the command line is invalid.
+// The usage has already been printed.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	e := &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	os.Exit(run(ctx, e, os.Args[1:]))
+}
+
+// run runs the command line args and returns the exit code.
+func run(ctx context.Context, e *env, args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		usage(e.stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	for _, cmd := range commands() {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(ctx, e, args[1:])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		default:
+			fmt.Fprintf(e.stderr, "okr4j %s: %s\n", cmd.name, err)
+			return 1
+		}
+	}
+	fmt.Fprintf(e.stderr, "okr4j: unknown command %q\n", args[0])
+	usage(e.stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: okr4j <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, cmd := range commands() {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.name, cmd.description)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "okr4j <command> -h" for the flags of a command.`)
+}
+
+// newFlagSet returns the flag set of the command, printing its usage to e.stderr.
+func newFlagSet(e *env, cmd string) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		for _, c := range commands() {
+			if c.name == cmd {
+				fmt.Fprintf(e.stderr, "Usage: okr4j %s [flags] %s\n\n%s\n\nFlags:\n", c.name, c.args, c.description)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the flags of the command and converts the errors to errUsage.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	return nil
+}
+
+// usageError prints msg and the usage of the command, and returns errUsage.
+func usageError(e *env, fs *flag.FlagSet, msg string) error {
+	fmt.Fprintf(e.stderr, "okr4j %s: %s\n", fs.Name(), msg)
+	fs.Usage()
+	return errUsage
+}
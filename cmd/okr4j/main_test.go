@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grandper/okrforjira"
+	"github.com/grandper/okrforjira/okrforjiratest"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// runTest runs the command line with the environment variables and returns
+// the exit code and the outputs.
+func runTest(t *testing.T, vars map[string]string, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	e := &env{
+		stdin:  strings.NewReader(stdin),
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(key string) string { return vars[key] },
+	}
+	code := run(context.Background(), e, args)
+	return code, stdout.String(), stderr.String()
+}
+
+func newTestServer(t *testing.T) (*okrforjiratest.Server, map[string]string) {
+	s := okrforjiratest.NewServer(okrforjiratest.DefaultSeed())
+	t.Cleanup(s.Close)
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(config, []byte("base_url: "+s.URL+"\n"), 0o600))
+	return s, map[string]string{tokenEnv: s.Token, configEnv: config}
+}
+
+func TestRun_Usage(t *testing.T) {
+	code, _, stderr := runTest(t, nil, "")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "objectives-by-date")
+
+	code, _, stderr = runTest(t, nil, "", "unknown")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "unknown"`)
+
+	code, _, _ = runTest(t, nil, "", "help")
+	assert.Equal(t, 0, code)
+
+	code, _, stderr = runTest(t, nil, "", "objectives-by-date", "-start", "2022-01-01")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "-start and -deadline are required")
+
+	code, _, stderr = runTest(t, nil, "", "key-results-by-ids")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "at least one ID is required")
+
+	code, _, stderr = runTest(t, nil, "", "key-results-by-ids", "-output", "xml", "kr1")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `invalid output format "xml"`)
+}
+
+func TestRun_Token(t *testing.T) {
+	s, vars := newTestServer(t)
+
+	t.Run("Missing token", func(t *testing.T) {
+		code, _, stderr := runTest(t, map[string]string{configEnv: vars[configEnv]}, "", "objectives-by-ids", "o1")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "missing API token")
+	})
+
+	t.Run("Token from the flag", func(t *testing.T) {
+		code, _, stderr := runTest(t, map[string]string{configEnv: vars[configEnv]}, "", "objectives-by-ids", "-token", s.Token, "o1")
+		assert.Equal(t, 0, code, stderr)
+	})
+
+	t.Run("Flag takes precedence", func(t *testing.T) {
+		code, _, stderr := runTest(t, vars, "", "objectives-by-ids", "-token", "wrong", "o1")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "error status 401")
+	})
+
+	t.Run("Token from the config file", func(t *testing.T) {
+		config := filepath.Join(t.TempDir(), "config.yaml")
+		assert.NoError(t, os.WriteFile(config, []byte("token: "+s.Token+"\nbase_url: "+s.URL+"\n"), 0o600))
+		code, _, stderr := runTest(t, nil, "", "objectives-by-ids", "-config", config, "o1")
+		assert.Equal(t, 0, code, stderr)
+	})
+
+	t.Run("Default config file", func(t *testing.T) {
+		home := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(home, ".config", "okr4j"), 0o700))
+		config := []byte("token: " + s.Token + "\nbase_url: " + s.URL + "\n")
+		assert.NoError(t, os.WriteFile(filepath.Join(home, ".config", "okr4j", "config.yaml"), config, 0o600))
+		code, _, stderr := runTest(t, map[string]string{"HOME": home}, "", "objectives-by-ids", "o1")
+		assert.Equal(t, 0, code, stderr)
+
+		xdg := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(xdg, "okr4j"), 0o700))
+		assert.NoError(t, os.WriteFile(filepath.Join(xdg, "okr4j", "config.yaml"), []byte("token: wrong\nbase_url: "+s.URL+"\n"), 0o600))
+		code, _, stderr = runTest(t, map[string]string{"HOME": home, "XDG_CONFIG_HOME": xdg}, "", "objectives-by-ids", "o1")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "error status 401")
+
+		// Without HOME, the default config file is not read.
+		code, _, stderr = runTest(t, nil, "", "objectives-by-ids", "o1")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "missing API token")
+	})
+
+	t.Run("Missing config file", func(t *testing.T) {
+		code, _, stderr := runTest(t, nil, "", "objectives-by-ids", "-config", filepath.Join(t.TempDir(), "missing.yaml"), "o1")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "failed to read the config file")
+	})
+}
+
+func TestRun_Export(t *testing.T) {
+	_, vars := newTestServer(t)
+
+	t.Run("Table", func(t *testing.T) {
+		code, stdout, stderr := runTest(t, vars, "", "objectives-by-date", "-start", "2022-01-01", "-deadline", "2022-03-31", "-expand", "key_results")
+		assert.Equal(t, 0, code, stderr)
+		want := strings.Join([]string{
+			"KEY  NAME                    STATUS    PROGRESS  START       DEADLINE    PERIOD   TEAMS",
+			"O-1  Grow the user base      ON TRACK  32%       2022-01-01  2022-03-31  Q1 2022  Product team",
+			"O-3  Improve the onboarding  -         20%       2022-01-01  2022-03-31  Q1 2022  Product team",
+			"",
+			"KEY   OBJECTIVE  NAME                                STATUS    VALUE       PROGRESS  DEADLINE    PERIOD   TEAMS",
+			"KR-1  O-1        Reach 10000 active users            ON TRACK  4000 users  25%       2022-03-31  Q1 2022  Product team",
+			"KR-2  O-1        Launch the referral program         -         -           50%       2022-03-31  Q1 2022  Product team",
+			"KR-4  O-3        Increase the onboarding conversion  AT RISK   33%         20%       2022-03-31  Q1 2022  Product team",
+			"",
+		}, "\n")
+		assert.Equal(t, want, stdout)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		code, stdout, stderr := runTest(t, vars, "", "key-results-by-ids", "-output", "json", "-expand-all", "kr3,kr1")
+		assert.Equal(t, 0, code, stderr)
+		var got okrforjira.Response
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &got))
+		assert.Len(t, got.KeyResults, 2)
+		assert.Len(t, got.OKRs, 2)
+		assert.Len(t, got.Teams, 2)
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		code, stdout, stderr := runTest(t, vars, "", "key-results-by-date", "-output", "yaml", "-start", "2022-04-01", "-deadline", "2022-06-30", "-expand", "TEAMS")
+		assert.Equal(t, 0, code, stderr)
+		assert.Contains(t, stdout, "krs:\n  - id: kr3\n    key: KR-3\n")
+		assert.Contains(t, stdout, "    startDate: 2022-04-01T00:00:00+0000\n")
+		var got map[string]interface{}
+		assert.NoError(t, yaml.Unmarshal([]byte(stdout), &got))
+		assert.Equal(t, []interface{}{map[string]interface{}{"id": "t-ops", "name": "Operations"}}, got["teams"])
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		code, stdout, stderr := runTest(t, vars, "", "key-results-by-ids", "-output", "csv", "-columns", "key,objective,owner,status,value,period,teams,labels", "kr1,kr4")
+		assert.Equal(t, 0, code, stderr)
+		want := "Key,Objective,Owner,Status,Value,Period,Teams,Labels\n" +
+			"KR-1,o1,account-1,ON_TRACK,4000,Q1 2022,Product team,growth\n" +
+			"KR-4,o3,account-3,AT_RISK,33,Q1 2022,Product team,\n"
+		assert.Equal(t, want, stdout)
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		code, stdout, stderr := runTest(t, vars, "", "objectives-by-date", "-output", "xlsx", "-start", "2022-01-01", "-deadline", "2022-06-30")
+		assert.Equal(t, 0, code, stderr)
+		f, err := excelize.OpenReader(strings.NewReader(stdout))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer f.Close()
+		assert.Equal(t, []string{"Q1 2022", "Q2 2022"}, f.GetSheetList())
+		rows, err := f.GetRows("Q2 2022")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Objective", "O-2", "Improve the reliability"}, rows[1][:3])
+	})
+
+	t.Run("Invalid columns", func(t *testing.T) {
+		code, _, stderr := runTest(t, vars, "", "objectives-by-ids", "-output", "csv", "-columns", "key,budget", "o1")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, `unknown column "budget"`)
+
+		code, _, stderr = runTest(t, vars, "", "objectives-by-ids", "-columns", "key", "o1")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, "-columns requires the csv or xlsx output")
+	})
+
+	t.Run("Invalid expand", func(t *testing.T) {
+		code, _, stderr := runTest(t, vars, "", "objectives-by-ids", "-expand", "USERS", "o1")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "invalid object USERS")
+	})
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"gopkg.in/yaml.v3"
+)
+
+// The output formats.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	// The spreadsheet formats are only supported by the export commands.
+	outputCSV  = "csv"
+	outputXLSX = "xlsx"
+)
+
+// writeResponse writes the response in the output format.
+func writeResponse(w io.Writer, format string, r okrforjira.Response) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(w, r)
+	case outputYAML:
+		return writeYAML(w, r)
+	}
+	return writeResponseTable(w, r)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeYAML writes v in YAML with the field names and date format of the
+// JSON encoding, which the model types define.
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML: decoding it in a node keeps the order of the fields.
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetStyle(&node)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// resetStyle removes the flow style and the quotes of the nodes decoded from
+// JSON. The encoder still quotes the strings when needed.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// writeResponseTable writes the objectives and the key results of the
+// response as tables, with the names of their teams and periods.
+func writeResponseTable(w io.Writer, r okrforjira.Response) error {
+	idx := okrforjira.NewIndex(r)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if len(r.OKRs) > 0 {
+		fmt.Fprintln(tw, "KEY\tNAME\tSTATUS\tPROGRESS\tSTART\tDEADLINE\tPERIOD\tTEAMS")
+		for _, o := range r.OKRs {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				o.Key, o.Name, formatStatus(o.LatestUpdate.Status), formatPercent(o.PercentDone),
+				formatDate(o.StartDate), formatDate(o.Deadline), periodName(idx, o), teamNames(idx, o))
+		}
+	}
+	if len(r.KeyResults) > 0 {
+		if len(r.OKRs) > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintln(tw, "KEY\tOBJECTIVE\tNAME\tSTATUS\tVALUE\tPROGRESS\tDEADLINE\tPERIOD\tTEAMS")
+		for _, kr := range r.KeyResults {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				kr.Key, parentName(idx, kr), kr.Name, formatStatus(kr.LatestUpdate.Status),
+				formatValue(kr.LatestUpdate.Value, kr.Unit), formatPercent(kr.PercentDone),
+				formatDate(kr.Deadline), periodName(idx, kr), teamNames(idx, kr))
+		}
+	}
+	return tw.Flush()
+}
+
+// writeUpdate writes the update in the output format.
+func writeUpdate(w io.Writer, format string, u okrforjira.Update) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(w, u)
+	case outputYAML:
+		return writeYAML(w, u)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Status:\t%s\n", formatStatus(u.Status))
+	fmt.Fprintf(tw, "Value:\t%s\n", formatValue(u.Value, okrforjira.Unit{}))
+	fmt.Fprintf(tw, "Created:\t%s\n", formatTime(u.Created))
+	fmt.Fprintf(tw, "Description:\t%s\n", u.Description)
+	return tw.Flush()
+}
+
+func formatStatus(s okrforjira.Status) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(string(s), "_", " ")
+}
+
+func formatPercent(p float64) string {
+	return strconv.FormatFloat(p, 'f', 0, 64) + "%"
+}
+
+func formatValue(v okrforjira.NullFloat64, unit okrforjira.Unit) string {
+	value, ok := v.Get()
+	if !ok {
+		return "-"
+	}
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if unit.Symbol != "" {
+		return formatted + unit.Symbol
+	}
+	if unit.Name != "" {
+		return formatted + " " + unit.Name
+	}
+	return formatted
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05 -0700")
+}
+
+func periodName(idx *okrforjira.Index, e okrforjira.Entity) string {
+	if p, ok := idx.PeriodOf(e); ok {
+		return p.Name
+	}
+	return "-"
+}
+
+func teamNames(idx *okrforjira.Index, e okrforjira.Entity) string {
+	var names []string
+	for _, t := range idx.TeamsOf(e) {
+		names = append(names, t.Name)
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ", ")
+}
+
+func parentName(idx *okrforjira.Index, kr okrforjira.KeyResult) string {
+	if o, ok := idx.ParentOf(kr); ok {
+		return o.Key
+	}
+	if kr.ParentObjectiveID != "" {
+		return kr.ParentObjectiveID
+	}
+	return "-"
+}
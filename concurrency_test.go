@@ -0,0 +1,92 @@
+package okrforjira_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_Concurrency calls all the methods of a single client concurrently.
+// It is meant to be run with the race detector.
+func TestClient_Concurrency(t *testing.T) {
+	fixtures := map[string][]byte{
+		"/api/v2/api-export/objectives/byDate": readFixture(t, "objectives_by_date.json"),
+		"/api/v2/api-export/objectives/byIds":  readFixture(t, "objectives_by_ids.json"),
+		"/api/v2/api-export/keyResults/byDate": readFixture(t, "key_results_by_date.json"),
+		"/api/v2/api-export/keyResults/byIds":  readFixture(t, "key_results_by_ids.json"),
+		"/api/v2/api-update/objectives":        readFixture(t, "update_objective.json"),
+		"/api/v2/api-update/keyResults":        readFixture(t, "update_key_result.json"),
+	}
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewReader(fixtures[req.URL.Path])),
+			Header:     make(http.Header),
+		}
+	})
+	c := okrforjira.NewClient(client, token)
+
+	ctx := context.Background()
+	startDate := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC)
+	expand := []string{"OBJECTIVES", "KEY_RESULTS", "TEAMS", "PERIODS", "LABELS"}
+	calls := []func() (string, error){
+		func() (string, error) {
+			r, err := c.ObjectivesByDate(ctx, startDate, deadline, expand)
+			return firstKey(r), err
+		},
+		func() (string, error) {
+			r, err := c.ObjectivesByIDs(ctx, []string{"5fda249d289742000406b3e4"}, expand)
+			return firstKey(r), err
+		},
+		func() (string, error) {
+			r, err := c.KeyResultsByDate(ctx, startDate, deadline, expand)
+			return firstKey(r), err
+		},
+		func() (string, error) {
+			r, err := c.KeyResultsByIDs(ctx, []string{"5fda249d289742000406b3e5"}, expand)
+			return firstKey(r), err
+		},
+		func() (string, error) {
+			u, err := c.UpdateObjective(ctx, "62334eac00ee2b102e34fdb7", okrforjira.StatusOnTrack, "")
+			return u.Created.Format(time.RFC3339), err
+		},
+		func() (string, error) {
+			u, err := c.UpdateKeyResult(ctx, "62384a6942adda046598b3bd", okrforjira.StatusAtRisk, 13500.5, "")
+			return u.Created.Format(time.RFC3339), err
+		},
+	}
+	want := []string{"O-2", "O-2", "O-99", "O-3", "2022-05-20T09:58:09Z", "2022-05-20T13:01:35Z"}
+
+	const goroutines = 20
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := range calls {
+				// Every goroutine starts with a different method.
+				j := (i + g) % len(calls)
+				got, err := calls[j]()
+				assert.NoError(t, err)
+				assert.Equal(t, want[j], strings.Replace(got, "+00:00", "Z", 1))
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func firstKey(r okrforjira.Response) string {
+	if len(r.OKRs) == 0 {
+		return ""
+	}
+	return r.OKRs[0].Key
+}
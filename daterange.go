@@ -0,0 +1,106 @@
+package okrforjira
+
+import (
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// DateRange is a range of dates, both bounds included.
+type DateRange struct {
+	StartDate time.Time
+	Deadline  time.Time
+}
+
+// DateSplitter splits a date range into consecutive windows.
+type DateSplitter func(startDate, deadline time.Time) []DateRange
+
+// WithDateSplitter splits the date range of ObjectivesByDate and KeyResultsByDate
+// into windows that are fetched separately, see WithBatchConcurrency, and merged.
+// This keeps the responses small when exporting long horizons.
+func WithDateSplitter(splitter DateSplitter) Option {
+	return func(c *config) {
+		c.dateSplitter = splitter
+	}
+}
+
+// SplitByMonth splits a date range into calendar months,
+// in the location of the start date.
+func SplitByMonth(startDate, deadline time.Time) []DateRange {
+	return splitByMonths(startDate, deadline, 1)
+}
+
+// SplitByQuarter splits a date range into calendar quarters,
+// in the location of the start date.
+func SplitByQuarter(startDate, deadline time.Time) []DateRange {
+	return splitByMonths(startDate, deadline, 3)
+}
+
+// SplitByPeriods returns a DateSplitter splitting a date range along the provided periods,
+// e.g. the periods of a previous response. The parts of the range that are not
+// covered by any period are returned as additional windows.
+func SplitByPeriods(periods []Period) DateSplitter {
+	sorted := make([]Period, len(periods))
+	copy(sorted, periods)
+	slices.SortFunc(sorted, func(a, b Period) bool {
+		return a.StartDate.Before(b.StartDate)
+	})
+
+	return func(startDate, deadline time.Time) []DateRange {
+		var ranges []DateRange
+		cursor := startDate
+		for _, p := range sorted {
+			if p.Deadline.Before(cursor) || p.StartDate.After(deadline) {
+				continue
+			}
+			if p.StartDate.After(cursor) {
+				ranges = append(ranges, DateRange{StartDate: cursor, Deadline: p.StartDate.Add(-time.Millisecond)})
+				cursor = p.StartDate
+			}
+			end := p.Deadline
+			if end.After(deadline) {
+				end = deadline
+			}
+			ranges = append(ranges, DateRange{StartDate: cursor, Deadline: end})
+			cursor = end.Add(time.Millisecond)
+		}
+		if !cursor.After(deadline) {
+			ranges = append(ranges, DateRange{StartDate: cursor, Deadline: deadline})
+		}
+		return ranges
+	}
+}
+
+func splitByMonths(startDate, deadline time.Time, months int) []DateRange {
+	if deadline.Before(startDate) {
+		return []DateRange{{StartDate: startDate, Deadline: deadline}}
+	}
+	// The windows are aligned on January, April, July and October for quarters.
+	month := startDate.Month() - (startDate.Month()-1)%time.Month(months)
+	next := time.Date(startDate.Year(), month, 1, 0, 0, 0, 0, startDate.Location())
+
+	var ranges []DateRange
+	cursor := startDate
+	for !cursor.After(deadline) {
+		next = next.AddDate(0, months, 0)
+		end := next.Add(-time.Millisecond)
+		if end.After(deadline) {
+			end = deadline
+		}
+		ranges = append(ranges, DateRange{StartDate: cursor, Deadline: end})
+		cursor = next
+	}
+	return ranges
+}
+
+// dateRanges returns the windows of the date range to fetch.
+func (c Client) dateRanges(startDate, deadline time.Time) []DateRange {
+	if c.dateSplitter == nil {
+		return []DateRange{{StartDate: startDate, Deadline: deadline}}
+	}
+	ranges := c.dateSplitter(startDate, deadline)
+	if len(ranges) == 0 {
+		return []DateRange{{StartDate: startDate, Deadline: deadline}}
+	}
+	return ranges
+}
@@ -0,0 +1,97 @@
+package okrforjira_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitByMonth(t *testing.T) {
+	startDate := time.Date(2022, time.January, 15, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2022, time.March, 10, 0, 0, 0, 0, time.UTC)
+	got := okrforjira.SplitByMonth(startDate, deadline)
+	want := []okrforjira.DateRange{
+		{StartDate: startDate, Deadline: time.Date(2022, time.January, 31, 23, 59, 59, 999000000, time.UTC)},
+		{StartDate: time.Date(2022, time.February, 1, 0, 0, 0, 0, time.UTC), Deadline: time.Date(2022, time.February, 28, 23, 59, 59, 999000000, time.UTC)},
+		{StartDate: time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC), Deadline: deadline},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestSplitByQuarter(t *testing.T) {
+	startDate := time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	got := okrforjira.SplitByQuarter(startDate, deadline)
+	want := []okrforjira.DateRange{
+		{StartDate: startDate, Deadline: time.Date(2021, time.June, 30, 23, 59, 59, 999000000, time.UTC)},
+		{StartDate: time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC), Deadline: time.Date(2021, time.September, 30, 23, 59, 59, 999000000, time.UTC)},
+		{StartDate: time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC), Deadline: time.Date(2021, time.December, 31, 23, 59, 59, 999000000, time.UTC)},
+		{StartDate: time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC), Deadline: deadline},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestSplitByPeriods(t *testing.T) {
+	periods := []okrforjira.Period{
+		{
+			ID:        "602a6a2717378700039f342c",
+			Name:      "Q3 Y2021",
+			StartDate: time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+			Deadline:  time.Date(2021, time.September, 30, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			ID:        "602a6a2717378700039f342a",
+			Name:      "Q1 Y2021",
+			StartDate: time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Deadline:  time.Date(2021, time.March, 31, 23, 59, 59, 0, time.UTC),
+		},
+	}
+	startDate := time.Date(2021, time.February, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2021, time.December, 31, 0, 0, 0, 0, time.UTC)
+	got := okrforjira.SplitByPeriods(periods)(startDate, deadline)
+	want := []okrforjira.DateRange{
+		{StartDate: startDate, Deadline: time.Date(2021, time.March, 31, 23, 59, 59, 0, time.UTC)},
+		{StartDate: time.Date(2021, time.March, 31, 23, 59, 59, 1000000, time.UTC), Deadline: time.Date(2021, time.June, 30, 23, 59, 59, 999000000, time.UTC)},
+		{StartDate: time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC), Deadline: time.Date(2021, time.September, 30, 23, 59, 59, 0, time.UTC)},
+		{StartDate: time.Date(2021, time.September, 30, 23, 59, 59, 1000000, time.UTC), Deadline: deadline},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestClient_DateSplitter(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		queries = append(queries, req.URL.RawQuery)
+		mu.Unlock()
+		// Every window returns the same objective.
+		return newJSONResponse(200, `{"okrs":[{"id":"o1"}],"krs":[{"id":"kr1"}]}`)
+	})
+	c := okrforjira.NewClient(client, token,
+		okrforjira.WithDateSplitter(okrforjira.SplitByMonth),
+		okrforjira.WithBatchConcurrency(2),
+	)
+	startDate := time.Date(2022, time.January, 15, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2022, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	got, err := c.ObjectivesByDate(context.Background(), startDate, deadline, []string{"KEY_RESULTS"})
+	assert.NoError(t, err)
+	assert.Equal(t, []okrforjira.OKR{{ID: "o1"}}, got.OKRs)
+	assert.Equal(t, []okrforjira.KeyResult{{ID: "kr1"}}, got.KeyResults)
+	assert.ElementsMatch(t, []string{
+		"startDateEpochMilli=1642204800000&deadlineEpochMilli=1643673599999&expand=KEY_RESULTS",
+		"startDateEpochMilli=1643673600000&deadlineEpochMilli=1646092799999&expand=KEY_RESULTS",
+		"startDateEpochMilli=1646092800000&deadlineEpochMilli=1646870400000&expand=KEY_RESULTS",
+	}, queries)
+
+	queries = nil
+	_, err = c.KeyResultsByDate(context.Background(), startDate, deadline, nil)
+	assert.NoError(t, err)
+	assert.Len(t, queries, 3)
+}
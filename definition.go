@@ -0,0 +1,102 @@
+package okrforjira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ProgressType is the way the progress of a key result is measured.
+type ProgressType string
+
+// The progress types known by OKR for Jira.
+const (
+	// ProgressTypeStandard measures the progress from a start value to a desired value.
+	ProgressTypeStandard ProgressType = "STANDARD"
+	// ProgressTypeAuto measures the progress as the ratio of done issues matching a JQL query.
+	ProgressTypeAuto ProgressType = "AUTO"
+)
+
+// NullFloat64 is a number that may be null in the API.
+type NullFloat64 struct {
+	Float64 float64
+	// Valid is true if the number is not null.
+	Valid bool
+}
+
+// NewNullFloat64 returns a valid NullFloat64 holding f.
+func NewNullFloat64(f float64) NullFloat64 {
+	return NullFloat64{Float64: f, Valid: true}
+}
+
+// Get returns the number and whether it is not null.
+func (n NullFloat64) Get() (float64, bool) {
+	return n.Float64, n.Valid
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (n *NullFloat64) UnmarshalJSON(data []byte) error {
+	var value *float64
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == nil {
+		*n = NullFloat64{}
+		return nil
+	}
+	*n = NewNullFloat64(*value)
+	return nil
+}
+
+// ErrNoProgress is returned when the progress of a key result cannot be computed.
+var ErrNoProgress = errors.New("progress cannot be computed")
+
+// Percent returns the completion, in percent between 0 and 100, of a STANDARD
+// progress definition for the current value. The desired value can be greater
+// than the start value for increasing key results, or lower for decreasing ones.
+// The completion of an AUTO progress definition depends on the issues of the
+// key result, see KeyResult.Percent.
+func (d ProgressDefinition) Percent(current float64) (float64, error) {
+	if d.Type != ProgressTypeStandard {
+		return 0, fmt.Errorf("%w from the value of a %s key result", ErrNoProgress, d.Type)
+	}
+	start, ok := d.StartValue.Get()
+	if !ok {
+		return 0, fmt.Errorf("%w without start value", ErrNoProgress)
+	}
+	desired, ok := d.DesiredValue.Get()
+	if !ok {
+		return 0, fmt.Errorf("%w without desired value", ErrNoProgress)
+	}
+	if desired == start {
+		if current == desired {
+			return 100, nil
+		}
+		return 0, nil
+	}
+	percent := (current - start) / (desired - start) * 100
+	return math.Max(0, math.Min(100, percent)), nil
+}
+
+// Percent returns the completion, in percent between 0 and 100, of the key
+// result for the current value. For an AUTO key result, the current value is
+// the number of done issues among IssueIDs.
+func (kr KeyResult) Percent(current float64) (float64, error) {
+	if kr.CurrentProgressDefinition.Type != ProgressTypeAuto {
+		return kr.CurrentProgressDefinition.Percent(current)
+	}
+	if len(kr.IssueIDs) == 0 {
+		return 0, fmt.Errorf("%w for an AUTO key result without issues", ErrNoProgress)
+	}
+	percent := current / float64(len(kr.IssueIDs)) * 100
+	return math.Max(0, math.Min(100, percent)), nil
+}
@@ -0,0 +1,70 @@
+package okrforjira_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullFloat64_JSON(t *testing.T) {
+	var def okrforjira.ProgressDefinition
+	assert.NoError(t, json.Unmarshal([]byte(`{"type":"STANDARD","startValue":0.0,"desiredValue":null}`), &def))
+	assert.Equal(t, okrforjira.ProgressDefinition{
+		Type:       okrforjira.ProgressTypeStandard,
+		StartValue: okrforjira.NewNullFloat64(0),
+	}, def)
+
+	data, err := json.Marshal(def)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"STANDARD","startValue":0,"desiredValue":null,"jql":""}`, string(data))
+}
+
+func TestProgressDefinition_Percent(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   float64
+		desired float64
+		current float64
+		want    float64
+	}{
+		{"Increasing", 0, 22, 11, 50},
+		{"Increasing from a start value", 1, 2, 1, 0},
+		{"Increasing above the desired value", 0, 10, 15, 100},
+		{"Decreasing", 100, 50, 75, 50},
+		{"Decreasing below the desired value", 100, 50, 20, 100},
+		{"Decreasing above the start value", 100, 50, 120, 0},
+		{"Desired value reached", 5, 5, 5, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := okrforjira.ProgressDefinition{
+				Type:         okrforjira.ProgressTypeStandard,
+				StartValue:   okrforjira.NewNullFloat64(tt.start),
+				DesiredValue: okrforjira.NewNullFloat64(tt.desired),
+			}
+			got, err := def.Percent(tt.current)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+
+	t.Run("Missing values", func(t *testing.T) {
+		def := okrforjira.ProgressDefinition{Type: okrforjira.ProgressTypeStandard, StartValue: okrforjira.NewNullFloat64(0)}
+		_, err := def.Percent(1)
+		assert.ErrorIs(t, err, okrforjira.ErrNoProgress)
+		assert.EqualError(t, err, "progress cannot be computed without desired value")
+	})
+
+	t.Run("AUTO", func(t *testing.T) {
+		def := okrforjira.ProgressDefinition{Type: okrforjira.ProgressTypeAuto, JQL: "project= TEST"}
+		_, err := def.Percent(1)
+		assert.ErrorIs(t, err, okrforjira.ErrNoProgress)
+
+		kr := okrforjira.KeyResult{IssueIDs: []string{"10005", "10006", "10010"}, CurrentProgressDefinition: def}
+		got, err := kr.Percent(1)
+		assert.NoError(t, err)
+		assert.InDelta(t, 33.33333333333333, got, 1e-9)
+	})
+}
@@ -0,0 +1,350 @@
+package okrforjira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// DriftKind is the kind of a Drift.
+type DriftKind string
+
+// The kinds of drift between the API and the model types.
+const (
+	// DriftUnknownField is a field sent by the API that has no counterpart
+	// in the model types. Its value is dropped when decoding.
+	DriftUnknownField DriftKind = "UNKNOWN_FIELD"
+	// DriftTypeMismatch is a field whose JSON type does not match the type
+	// of the model field. Decoding usually fails.
+	DriftTypeMismatch DriftKind = "TYPE_MISMATCH"
+	// DriftUnknownValue is a value that is not one of the values known by
+	// the model, e.g. a new status. The value is kept when decoding.
+	DriftUnknownValue DriftKind = "UNKNOWN_VALUE"
+)
+
+// Drift is a difference between a response of the API and the model types,
+// e.g. a field added by a new version of the API.
+type Drift struct {
+	Kind DriftKind
+	// Entity is the name of the model type, e.g. "KeyResult" or "Update".
+	Entity string
+	// Field is the JSON name of the field, e.g. "percentDone".
+	Field string
+	// Expected is the expected JSON type, empty for an unknown field.
+	Expected string
+	// Got is the JSON type sent by the API, e.g. "string" or "object",
+	// or the value itself for an unknown value.
+	Got string
+	// Count is the number of times the drift was found.
+	Count int
+}
+
+// String implements the fmt.Stringer interface.
+func (d Drift) String() string {
+	if d.Kind == DriftUnknownField {
+		return fmt.Sprintf("%s.%s: unknown field of type %s (%d times)", d.Entity, d.Field, d.Got, d.Count)
+	}
+	if d.Kind == DriftUnknownValue {
+		return fmt.Sprintf("%s.%s: unknown %s %q (%d times)", d.Entity, d.Field, d.Expected, d.Got, d.Count)
+	}
+	return fmt.Sprintf("%s.%s: expected %s, got %s (%d times)", d.Entity, d.Field, d.Expected, d.Got, d.Count)
+}
+
+// DriftReport collects the drifts found in the responses of the API.
+// It is safe for concurrent use, so a single report can be shared by the
+// requests of a client, see WithDriftReport.
+type DriftReport struct {
+	mu     sync.Mutex
+	drifts map[driftKey]*Drift
+}
+
+type driftKey struct {
+	kind          DriftKind
+	entity, field string
+	got           string
+}
+
+// Add adds the drifts to the report, summing the counts of identical drifts.
+func (r *DriftReport) Add(drifts ...Drift) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.drifts == nil {
+		r.drifts = make(map[driftKey]*Drift)
+	}
+	for _, d := range drifts {
+		key := driftKey{kind: d.Kind, entity: d.Entity, field: d.Field, got: d.Got}
+		if existing, ok := r.drifts[key]; ok {
+			existing.Count += d.Count
+			continue
+		}
+		d := d
+		r.drifts[key] = &d
+	}
+}
+
+// Drifts returns the drifts of the report sorted by entity and field.
+func (r *DriftReport) Drifts() []Drift {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	drifts := make([]Drift, 0, len(r.drifts))
+	for _, d := range r.drifts {
+		drifts = append(drifts, *d)
+	}
+	sortDrifts(drifts)
+	return drifts
+}
+
+// Len returns the number of distinct drifts of the report.
+func (r *DriftReport) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.drifts)
+}
+
+// Err returns a *DriftError with the drifts of the report,
+// or nil if the report is empty.
+func (r *DriftReport) Err() error {
+	drifts := r.Drifts()
+	if len(drifts) == 0 {
+		return nil
+	}
+	return &DriftError{Drifts: drifts}
+}
+
+// DriftError is returned when a response of the API does not match the
+// model types and the client uses WithStrictDecoding.
+type DriftError struct {
+	Drifts []Drift
+}
+
+// Error implements the error interface.
+func (e *DriftError) Error() string {
+	msgs := make([]string, len(e.Drifts))
+	for i, d := range e.Drifts {
+		msgs[i] = d.String()
+	}
+	return "API schema drift: " + strings.Join(msgs, "; ")
+}
+
+// WithDriftReport records in report the drifts between the responses of the
+// API and the model types, e.g. unknown fields. The responses are decoded as
+// usual unless WithStrictDecoding is also used.
+func WithDriftReport(report *DriftReport) Option {
+	return func(c *config) {
+		c.driftReport = report
+	}
+}
+
+// WithStrictDecoding makes the client methods fail with a *DriftError when a
+// response of the API does not match the model types, e.g. because of an
+// unknown field, instead of silently dropping the unknown fields.
+func WithStrictDecoding() Option {
+	return func(c *config) {
+		c.strictDecoding = true
+	}
+}
+
+// CheckDrift returns the drifts between the export response in data and the
+// model types, e.g. to check a cached response.
+func CheckDrift(data []byte) ([]Drift, error) {
+	return findDrift(data, responseType)
+}
+
+var (
+	responseType    = reflect.TypeOf(Response{})
+	timeType        = reflect.TypeOf(time.Time{})
+	nullFloat64Type = reflect.TypeOf(NullFloat64{})
+	statusType      = reflect.TypeOf(Status(""))
+)
+
+// findDrift returns the drifts between the JSON document in data and t.
+func findDrift(data []byte, t reflect.Type) ([]Drift, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	var report DriftReport
+	checkValue(&report, t.Name(), "", t, value)
+	return report.Drifts(), nil
+}
+
+// checkValue adds to report the drifts between value and t,
+// where value is the field of entity decoded in a generic way.
+func checkValue(report *DriftReport, entity, field string, t reflect.Type, value interface{}) {
+	if value == nil {
+		return
+	}
+	got := jsonType(value)
+	expected := expectedJSONType(t)
+	if got != expected && !(t == timeType && got == "number") {
+		report.Add(Drift{Kind: DriftTypeMismatch, Entity: entity, Field: field, Expected: expected, Got: got, Count: 1})
+		return
+	}
+	switch v := value.(type) {
+	case string:
+		if t == statusType && v != "" && Status(v).Validate() != nil {
+			report.Add(Drift{Kind: DriftUnknownValue, Entity: entity, Field: field, Expected: "status", Got: v, Count: 1})
+		}
+	case []interface{}:
+		for _, item := range v {
+			checkValue(report, entity, field, t.Elem(), item)
+		}
+	case map[string]interface{}:
+		checkObject(report, t, v)
+	}
+}
+
+// checkObject adds to report the drifts between the fields of object and the
+// fields of the struct t.
+func checkObject(report *DriftReport, t reflect.Type, object map[string]interface{}) {
+	fields := jsonFields(t)
+	for name, value := range object {
+		ft, ok := fields[name]
+		if !ok {
+			report.Add(Drift{Kind: DriftUnknownField, Entity: t.Name(), Field: name, Got: jsonType(value), Count: 1})
+			continue
+		}
+		checkValue(report, t.Name(), name, ft, value)
+	}
+}
+
+// expectedJSONType returns the JSON type of the values of t.
+func expectedJSONType(t reflect.Type) string {
+	switch t {
+	case timeType:
+		return "string"
+	case nullFloat64Type:
+		return "number"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		return "number"
+	case reflect.Slice:
+		return "array"
+	case reflect.Struct:
+		return "object"
+	}
+	return t.Kind().String()
+}
+
+// jsonType returns the JSON type of a value decoded in an interface{}.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+var fieldsCache sync.Map // map[reflect.Type]map[string]reflect.Type
+
+// jsonFields returns the types of the fields of the struct t by JSON name.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	if fields, ok := fieldsCache.Load(t); ok {
+		return fields.(map[string]reflect.Type)
+	}
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	fieldsCache.Store(t, fields)
+	return fields
+}
+
+func sortDrifts(drifts []Drift) {
+	slices.SortFunc(drifts, func(a, b Drift) bool {
+		if a.Entity != b.Entity {
+			return a.Entity < b.Entity
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Got < b.Got
+	})
+}
+
+// driftChecker checks the responses of a client for drift when it uses
+// WithDriftReport or WithStrictDecoding.
+type driftChecker struct {
+	report *DriftReport
+	strict bool
+}
+
+func (c Client) driftChecker() *driftChecker {
+	if c.driftReport == nil && !c.strictDecoding {
+		return nil
+	}
+	return &driftChecker{report: c.driftReport, strict: c.strictDecoding}
+}
+
+// check records the drifts between data and t, and returns a *DriftError
+// in strict mode when drifts are found.
+func (d *driftChecker) check(data []byte, t reflect.Type) error {
+	drifts, err := findDrift(data, t)
+	if err != nil {
+		return err
+	}
+	return d.add(drifts...)
+}
+
+// add records the drifts, and returns a *DriftError in strict mode.
+func (d *driftChecker) add(drifts ...Drift) error {
+	if len(drifts) == 0 {
+		return nil
+	}
+	if d.report != nil {
+		d.report.Add(drifts...)
+	}
+	if d.strict {
+		return &DriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// decode decodes the JSON document read from r into v, checking it for
+// drift first when d is not nil.
+func (d *driftChecker) decode(r io.Reader, v interface{}) error {
+	if d == nil {
+		return json.NewDecoder(r).Decode(v)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := d.check(data, reflect.TypeOf(v).Elem()); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
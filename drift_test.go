@@ -0,0 +1,129 @@
+package okrforjira_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDrift(t *testing.T) {
+	t.Run("Fixtures match the model", func(t *testing.T) {
+		fixtures := []string{
+			"objectives_by_date.json",
+			"objectives_by_ids.json",
+			"key_results_by_date.json",
+			"key_results_by_ids.json",
+		}
+		for _, fixture := range fixtures {
+			drifts, err := okrforjira.CheckDrift(readFixture(t, fixture))
+			assert.NoError(t, err)
+			assert.Empty(t, drifts, fixture)
+		}
+	})
+
+	t.Run("Unknown fields and type mismatches", func(t *testing.T) {
+		data := `{
+			"okrs": [
+				{"id": "o1", "confidence": 0.5, "latestUpdate": {"status": "ON_TRACK", "mood": "happy"}},
+				{"id": "o2", "confidence": 0.7, "weight": "1", "teamIds": ["t1", 2], "latestUpdate": {"status": "ON FIRE"}}
+			],
+			"krs": [{"id": "kr1", "created": 1617235199000, "currentProgressDefinition": {"startValue": "0"}}],
+			"owners": []
+		}`
+		drifts, err := okrforjira.CheckDrift([]byte(data))
+		assert.NoError(t, err)
+		want := []okrforjira.Drift{
+			{Kind: okrforjira.DriftUnknownField, Entity: "OKR", Field: "confidence", Got: "number", Count: 2},
+			{Kind: okrforjira.DriftTypeMismatch, Entity: "OKR", Field: "teamIds", Expected: "string", Got: "number", Count: 1},
+			{Kind: okrforjira.DriftTypeMismatch, Entity: "OKR", Field: "weight", Expected: "number", Got: "string", Count: 1},
+			{Kind: okrforjira.DriftTypeMismatch, Entity: "ProgressDefinition", Field: "startValue", Expected: "number", Got: "string", Count: 1},
+			{Kind: okrforjira.DriftUnknownField, Entity: "Response", Field: "owners", Got: "array", Count: 1},
+			{Kind: okrforjira.DriftUnknownField, Entity: "Update", Field: "mood", Got: "string", Count: 1},
+			{Kind: okrforjira.DriftUnknownValue, Entity: "Update", Field: "status", Expected: "status", Got: "ON FIRE", Count: 1},
+		}
+		assert.Equal(t, want, drifts)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := okrforjira.CheckDrift([]byte(`{`))
+		assert.Error(t, err)
+	})
+}
+
+func TestDriftReport(t *testing.T) {
+	var report okrforjira.DriftReport
+	assert.NoError(t, report.Err())
+	assert.Equal(t, 0, report.Len())
+
+	unknown := okrforjira.Drift{Kind: okrforjira.DriftUnknownField, Entity: "Team", Field: "color", Got: "string", Count: 1}
+	report.Add(unknown, unknown)
+	assert.Equal(t, 1, report.Len())
+	assert.Equal(t, 2, report.Drifts()[0].Count)
+
+	var driftErr *okrforjira.DriftError
+	assert.True(t, errors.As(report.Err(), &driftErr))
+	assert.EqualError(t, driftErr, "API schema drift: Team.color: unknown field of type string (2 times)")
+}
+
+func TestClient_Drift(t *testing.T) {
+	ctx := context.Background()
+	body := `{"okrs": [{"id": "o1", "confidence": 0.5}], "teams": [{"id": "t1", "name": "Team"}]}`
+	newClient := func(opts ...okrforjira.Option) *okrforjira.Client {
+		return okrforjira.NewClient(NewTestClient(func(req *http.Request) *http.Response {
+			return newJSONResponse(200, body)
+		}), token, opts...)
+	}
+	want := []okrforjira.Drift{{Kind: okrforjira.DriftUnknownField, Entity: "OKR", Field: "confidence", Got: "number", Count: 1}}
+
+	t.Run("Drifts are reported", func(t *testing.T) {
+		var report okrforjira.DriftReport
+		c := newClient(okrforjira.WithDriftReport(&report))
+		got, err := c.ObjectivesByIDs(ctx, []string{"o1"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []okrforjira.OKR{{ID: "o1"}}, got.OKRs)
+		assert.Equal(t, want, report.Drifts())
+	})
+
+	t.Run("Strict decoding fails", func(t *testing.T) {
+		var report okrforjira.DriftReport
+		c := newClient(okrforjira.WithDriftReport(&report), okrforjira.WithStrictDecoding())
+		_, err := c.ObjectivesByIDs(ctx, []string{"o1"}, nil)
+		var driftErr *okrforjira.DriftError
+		assert.True(t, errors.As(err, &driftErr))
+		assert.Equal(t, want, driftErr.Drifts)
+		assert.Equal(t, want, report.Drifts())
+	})
+
+	t.Run("Streamed objects are checked", func(t *testing.T) {
+		var report okrforjira.DriftReport
+		c := newClient(okrforjira.WithDriftReport(&report))
+		var ids []string
+		err := c.StreamObjectivesByIDs(ctx, []string{"o1"}, okrforjira.StreamHandler{
+			OKR: func(o okrforjira.OKR) error { ids = append(ids, o.ID); return nil },
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"o1"}, ids)
+		assert.Equal(t, want, report.Drifts())
+
+		c = newClient(okrforjira.WithStrictDecoding())
+		err = c.StreamObjectivesByIDs(ctx, []string{"o1"}, okrforjira.StreamHandler{
+			OKR: func(o okrforjira.OKR) error { return nil },
+		})
+		var driftErr *okrforjira.DriftError
+		assert.True(t, errors.As(err, &driftErr))
+	})
+
+	t.Run("Updates are checked", func(t *testing.T) {
+		var report okrforjira.DriftReport
+		c := okrforjira.NewClient(NewTestClient(func(req *http.Request) *http.Response {
+			return newJSONResponse(200, `{"entityId": "o1", "status": "ON_TRACK", "author": "me"}`)
+		}), token, okrforjira.WithDriftReport(&report))
+		_, err := c.UpdateObjective(ctx, "o1", okrforjira.StatusOnTrack, "")
+		assert.NoError(t, err)
+		assert.Equal(t, []okrforjira.Drift{{Kind: okrforjira.DriftUnknownField, Entity: "Update", Field: "author", Got: "string", Count: 1}}, report.Drifts())
+	})
+}
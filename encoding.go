@@ -0,0 +1,225 @@
+package okrforjira
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The model types implement json.Marshaler and json.Unmarshaler so that their
+// dates use the format of OKR for Jira, e.g. "2021-03-31T23:59:59+0000", with
+// the standard encoding/json package. A Response can then be cached on disk and
+// read back as if it was returned by the API. Zero dates are encoded as null.
+// Dates are decoded leniently, see ParseTime.
+
+// jsonTimeFormat is dateFormat with the fractional seconds, if any, so that
+// the dates with milliseconds are encoded without loss.
+const jsonTimeFormat = "2006-01-02T15:04:05.999999999-0700"
+
+// jsonTime is a time encoded in the format of OKR for Jira.
+type jsonTime time.Time
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t jsonTime) MarshalJSON() ([]byte, error) {
+	if time.Time(t).IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + time.Time(t).Format(jsonTimeFormat) + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The dates are parsed with ParseTime, and can also be epoch milliseconds.
+// The dates never contain escaped characters, so that they are parsed
+// without unquoting them first.
+func (t *jsonTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" || string(data) == `""` {
+		*t = jsonTime{}
+		return nil
+	}
+	if len(data) > 0 && data[0] != '"' {
+		parsed, err := parseEpochMilli(string(data))
+		if err != nil {
+			return fmt.Errorf("invalid date %s", data)
+		}
+		*t = jsonTime(parsed)
+		return nil
+	}
+	if len(data) < 2 || data[len(data)-1] != '"' {
+		return fmt.Errorf("invalid date %s", data)
+	}
+	parsed, err := ParseTime(string(data[1 : len(data)-1]))
+	if err != nil {
+		return err
+	}
+	*t = jsonTime(parsed)
+	return nil
+}
+
+// timeLayouts are the layouts accepted by ParseTime, in the order they are tried.
+var timeLayouts = []string{
+	dateFormat,                  // 2021-03-31T23:59:59+0000
+	"2006-01-02T15:04:05Z0700",  // 2021-03-31T23:59:59Z
+	time.RFC3339,                // 2021-03-31T23:59:59+00:00
+	"2006-01-02T15:04:05Z07",    // 2021-03-31T23:59:59+02
+	"2006-01-02 15:04:05Z07:00", // 2021-03-31 23:59:59+00:00
+}
+
+// ParseTime parses a date returned by OKR for Jira.
+// Besides the documented format, e.g. "2021-03-31T23:59:59+0000", it accepts
+// the RFC 3339 variants, e.g. "2021-03-31T23:59:59.123Z", and epoch
+// milliseconds, e.g. "1617235199000". Fractional seconds are always accepted.
+// The offset of the date is preserved, epoch milliseconds are in UTC.
+func ParseTime(value string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	if parsed, err := parseEpochMilli(value); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+func parseEpochMilli(value string) (time.Time, error) {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms).UTC(), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (o OKR) MarshalJSON() ([]byte, error) {
+	type alias OKR
+	return json.Marshal(struct {
+		alias
+		Created   jsonTime `json:"created"`
+		StartDate jsonTime `json:"startDate"`
+		Deadline  jsonTime `json:"deadline"`
+	}{
+		alias:     alias(o),
+		Created:   jsonTime(o.Created),
+		StartDate: jsonTime(o.StartDate),
+		Deadline:  jsonTime(o.Deadline),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (o *OKR) UnmarshalJSON(data []byte) error {
+	type alias OKR
+	aux := struct {
+		*alias
+		Created   jsonTime `json:"created"`
+		StartDate jsonTime `json:"startDate"`
+		Deadline  jsonTime `json:"deadline"`
+	}{alias: (*alias)(o)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	o.Created = time.Time(aux.Created)
+	o.StartDate = time.Time(aux.StartDate)
+	o.Deadline = time.Time(aux.Deadline)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (kr KeyResult) MarshalJSON() ([]byte, error) {
+	type alias KeyResult
+	return json.Marshal(struct {
+		alias
+		Created   jsonTime `json:"created"`
+		StartDate jsonTime `json:"startDate"`
+		Deadline  jsonTime `json:"deadline"`
+	}{
+		alias:     alias(kr),
+		Created:   jsonTime(kr.Created),
+		StartDate: jsonTime(kr.StartDate),
+		Deadline:  jsonTime(kr.Deadline),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (kr *KeyResult) UnmarshalJSON(data []byte) error {
+	type alias KeyResult
+	aux := struct {
+		*alias
+		Created   jsonTime `json:"created"`
+		StartDate jsonTime `json:"startDate"`
+		Deadline  jsonTime `json:"deadline"`
+	}{alias: (*alias)(kr)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	kr.Created = time.Time(aux.Created)
+	kr.StartDate = time.Time(aux.StartDate)
+	kr.Deadline = time.Time(aux.Deadline)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (u Update) MarshalJSON() ([]byte, error) {
+	type alias Update
+	return json.Marshal(struct {
+		alias
+		Created jsonTime `json:"created"`
+	}{
+		alias:   alias(u),
+		Created: jsonTime(u.Created),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (u *Update) UnmarshalJSON(data []byte) error {
+	type alias Update
+	aux := struct {
+		*alias
+		Created jsonTime `json:"created"`
+	}{alias: (*alias)(u)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	u.Created = time.Time(aux.Created)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (p Period) MarshalJSON() ([]byte, error) {
+	type alias Period
+	return json.Marshal(struct {
+		alias
+		StartDate jsonTime `json:"startDate"`
+		Deadline  jsonTime `json:"deadline"`
+	}{
+		alias:     alias(p),
+		StartDate: jsonTime(p.StartDate),
+		Deadline:  jsonTime(p.Deadline),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *Period) UnmarshalJSON(data []byte) error {
+	type alias Period
+	aux := struct {
+		*alias
+		StartDate jsonTime `json:"startDate"`
+		Deadline  jsonTime `json:"deadline"`
+	}{alias: (*alias)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	p.StartDate = time.Time(aux.StartDate)
+	p.Deadline = time.Time(aux.Deadline)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// A zero unit is encoded as null, as a missing unit is sent by the API.
+func (u Unit) MarshalJSON() ([]byte, error) {
+	if u == (Unit{}) {
+		return []byte("null"), nil
+	}
+	type alias Unit
+	return json.Marshal(alias(u))
+}
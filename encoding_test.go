@@ -0,0 +1,173 @@
+package okrforjira_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponse_JSONRoundTrip(t *testing.T) {
+	fixtures := []string{
+		"objectives_by_date.json",
+		"objectives_by_ids.json",
+		"key_results_by_date.json",
+		"key_results_by_ids.json",
+	}
+	for _, fixture := range fixtures {
+		fixture := fixture
+		t.Run(fixture, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewReader(readFixture(t, fixture))),
+					Header:     make(http.Header),
+				}
+			})
+			c := okrforjira.NewClient(client, token)
+			want, err := c.ObjectivesByIDs(context.Background(), []string{"1"}, nil)
+			assert.NoError(t, err)
+
+			data, err := json.Marshal(want)
+			assert.NoError(t, err)
+			var got okrforjira.Response
+			assert.NoError(t, json.Unmarshal(data, &got))
+			if !cmp.Equal(got, want) {
+				t.Errorf("unexpected result:\n%s", cmp.Diff(got, want))
+			}
+
+			again, err := json.Marshal(got)
+			assert.NoError(t, err)
+			assert.Equal(t, string(data), string(again))
+		})
+	}
+}
+
+func TestResponse_JSONRoundTripPrecision(t *testing.T) {
+	var want okrforjira.Response
+	assert.NoError(t, json.Unmarshal(readFixture(t, "precise_dates.json"), &want))
+	assert.Equal(t, 123*time.Millisecond, time.Duration(want.OKRs[0].Created.Nanosecond()))
+
+	data, err := json.Marshal(want)
+	assert.NoError(t, err)
+	var got okrforjira.Response
+	assert.NoError(t, json.Unmarshal(data, &got))
+	if !cmp.Equal(got, want) {
+		t.Errorf("unexpected result:\n%s", cmp.Diff(got, want))
+	}
+
+	var encoded struct {
+		OKRs []struct {
+			Created      string `json:"created"`
+			StartDate    string `json:"startDate"`
+			Deadline     string `json:"deadline"`
+			LatestUpdate struct {
+				Created string `json:"created"`
+			} `json:"latestUpdate"`
+		} `json:"okrs"`
+		KeyResults []struct {
+			Created string           `json:"created"`
+			Unit    *json.RawMessage `json:"unit"`
+		} `json:"krs"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &encoded))
+	o := encoded.OKRs[0]
+	assert.Equal(t, "2022-02-01T10:00:00.123+0100", o.Created)
+	assert.Equal(t, "2022-01-01T00:00:00+0000", o.StartDate)
+	assert.Equal(t, "2022-03-31T23:59:59.999+0000", o.Deadline)
+	assert.Equal(t, "2022-02-01T09:00:00.25+0000", o.LatestUpdate.Created)
+	assert.Equal(t, "2022-02-01T10:00:00+0000", encoded.KeyResults[0].Created)
+	// A null unit stays null.
+	assert.Nil(t, encoded.KeyResults[0].Unit)
+	assert.JSONEq(t, `{"name":"percent","symbol":"%"}`, string(*encoded.KeyResults[1].Unit))
+}
+
+func TestUpdate_JSON(t *testing.T) {
+	var update okrforjira.Update
+	assert.NoError(t, json.Unmarshal(readFixture(t, "update_key_result.json"), &update))
+	want := okrforjira.Update{
+		EntityID:    "62384a6942adda046598b3bd",
+		Status:      okrforjira.StatusAtRisk,
+		Created:     time.Date(2022, time.May, 20, 13, 1, 35, 0, time.UTC),
+		Value:       okrforjira.NewNullFloat64(13500.5),
+		Description: "Reduction in ship hull output is caused by Unobtainium supply disruptions.",
+	}
+	if !cmp.Equal(update, want) {
+		t.Errorf("unexpected result:\n%s", cmp.Diff(update, want))
+	}
+
+	data, err := json.Marshal(update)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+	"entityId": "62384a6942adda046598b3bd",
+	"status": "AT_RISK",
+	"created": "2022-05-20T13:01:35+0000",
+	"value": 13500.5,
+	"description": "Reduction in ship hull output is caused by Unobtainium supply disruptions."
+}`, string(data))
+
+	t.Run("Zero dates are null", func(t *testing.T) {
+		data, err := json.Marshal(okrforjira.Period{ID: "p1"})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"id":"p1","name":"","startDate":null,"deadline":null}`, string(data))
+
+		var period okrforjira.Period
+		assert.NoError(t, json.Unmarshal(data, &period))
+		assert.Equal(t, okrforjira.Period{ID: "p1"}, period)
+	})
+
+	t.Run("Invalid dates", func(t *testing.T) {
+		var period okrforjira.Period
+		err := json.Unmarshal([]byte(`{"startDate":"31/03/2021"}`), &period)
+		assert.ErrorContains(t, err, `invalid date "31/03/2021"`)
+	})
+}
+
+func TestParseTime(t *testing.T) {
+	plus2 := time.FixedZone("", 2*60*60)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2021-03-31T23:59:59+0000", time.Date(2021, time.March, 31, 23, 59, 59, 0, time.UTC)},
+		{"2021-03-31T23:59:59+0200", time.Date(2021, time.March, 31, 23, 59, 59, 0, plus2)},
+		{"2021-03-31T23:59:59.123+0200", time.Date(2021, time.March, 31, 23, 59, 59, 123e6, plus2)},
+		{"2021-03-31T23:59:59Z", time.Date(2021, time.March, 31, 23, 59, 59, 0, time.UTC)},
+		{"2021-03-31T23:59:59.123456Z", time.Date(2021, time.March, 31, 23, 59, 59, 123456e3, time.UTC)},
+		{"2021-03-31T23:59:59+02:00", time.Date(2021, time.March, 31, 23, 59, 59, 0, plus2)},
+		{"2021-03-31T23:59:59+02", time.Date(2021, time.March, 31, 23, 59, 59, 0, plus2)},
+		{"2021-03-31 23:59:59+02:00", time.Date(2021, time.March, 31, 23, 59, 59, 0, plus2)},
+		{"1617235199000", time.Date(2021, time.March, 31, 23, 59, 59, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := okrforjira.ParseTime(tt.value)
+		assert.NoError(t, err, tt.value)
+		assert.True(t, got.Equal(tt.want), "%s: got %s, want %s", tt.value, got, tt.want)
+		_, gotOffset := got.Zone()
+		_, wantOffset := tt.want.Zone()
+		assert.Equal(t, wantOffset, gotOffset, tt.value)
+	}
+
+	for _, value := range []string{"", "2021-03-31", "31/03/2021", "2021-03-31T23:59:59"} {
+		_, err := okrforjira.ParseTime(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestPeriod_JSONLenientDates(t *testing.T) {
+	var p okrforjira.Period
+	data := `{"id": "p1", "startDate": 1617235199000, "deadline": "2021-06-30T23:59:59.999Z"}`
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+	assert.True(t, p.StartDate.Equal(time.Date(2021, time.March, 31, 23, 59, 59, 0, time.UTC)))
+	assert.True(t, p.Deadline.Equal(time.Date(2021, time.June, 30, 23, 59, 59, 999e6, time.UTC)))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"startDate": true}`), &p))
+	assert.Error(t, json.Unmarshal([]byte(`{"startDate": "tomorrow"}`), &p))
+}
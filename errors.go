@@ -0,0 +1,111 @@
+package okrforjira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Sentinel errors matching the category of an APIError.
+// They can be used with errors.Is on any error returned by the Client methods.
+var (
+	// ErrUnauthorized is matched by 401 and 403 responses, e.g. for an invalid token.
+	ErrUnauthorized = errors.New("okrforjira: unauthorized")
+	// ErrNotFound is matched by 404 responses.
+	ErrNotFound = errors.New("okrforjira: not found")
+	// ErrRateLimited is matched by 429 responses.
+	ErrRateLimited = errors.New("okrforjira: rate limited")
+	// ErrServer is matched by 5xx responses.
+	ErrServer = errors.New("okrforjira: server error")
+)
+
+// APIError is returned when the OKR for Jira API responds with an unexpected status code.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Method is the HTTP method of the request.
+	Method string
+	// Endpoint is the path of the request, without the query string.
+	Endpoint string
+	// Body is the raw body of the response, or the error reading it.
+	Body []byte
+	// ErrorBody is the body of the response when it could be parsed as JSON, nil otherwise.
+	ErrorBody *ErrorBody
+	// RetryAfter is the delay requested by the server with the Retry-After header, or 0.
+	RetryAfter time.Duration
+}
+
+// ErrorBody is the JSON document sent by the server along with an error status.
+type ErrorBody struct {
+	Status  int    `json:"status"`
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	Path    string `json:"path"`
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	msg := string(e.Body)
+	if e.ErrorBody != nil && e.ErrorBody.Message != "" {
+		msg = e.ErrorBody.Message
+	}
+	return fmt.Sprintf("%s %s: error status %d: %s", e.Method, e.Endpoint, e.StatusCode, msg)
+}
+
+// Is reports whether the error matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500 && e.StatusCode <= 599
+	}
+	return false
+}
+
+func newAPIError(req *http.Request, r *http.Response) error {
+	content, readErr := ioutil.ReadAll(r.Body)
+	if readErr != nil {
+		content = []byte(fmt.Sprintf("failed to read the body: %s", readErr))
+	}
+	apiErr := &APIError{
+		StatusCode: r.StatusCode,
+		Method:     req.Method,
+		Endpoint:   req.URL.Path,
+		Body:       content,
+		RetryAfter: parseRetryAfter(r.Header.Get("Retry-After"), time.Now()),
+	}
+	var body ErrorBody
+	if err := json.Unmarshal(content, &body); err == nil {
+		apiErr.ErrorBody = &body
+	}
+	return apiErr
+}
+
+// parseRetryAfter parses the value of a Retry-After header,
+// which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package okrforjira_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		sentinel   error
+	}{
+		{"Unauthorized", 401, okrforjira.ErrUnauthorized},
+		{"Forbidden", 403, okrforjira.ErrUnauthorized},
+		{"Not found", 404, okrforjira.ErrNotFound},
+		{"Rate limited", 429, okrforjira.ErrRateLimited},
+		{"Internal server error", 500, okrforjira.ErrServer},
+		{"Service unavailable", 503, okrforjira.ErrServer},
+	}
+	sentinels := []error{
+		okrforjira.ErrUnauthorized,
+		okrforjira.ErrNotFound,
+		okrforjira.ErrRateLimited,
+		okrforjira.ErrServer,
+	}
+
+	ctx := context.Background()
+	startDate := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				header := make(http.Header)
+				header.Set("Retry-After", "120")
+				return &http.Response{
+					StatusCode: tt.statusCode,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"status":0,"error":"failure","message":"something went wrong","path":"/api"}`)),
+					Header:     header,
+				}
+			})
+			c := okrforjira.NewClient(client, token)
+
+			errs := map[string]error{}
+			_, errs["ObjectivesByDate"] = c.ObjectivesByDate(ctx, startDate, deadline, nil)
+			_, errs["ObjectivesByIDs"] = c.ObjectivesByIDs(ctx, []string{"1"}, nil)
+			_, errs["KeyResultsByDate"] = c.KeyResultsByDate(ctx, startDate, deadline, nil)
+			_, errs["KeyResultsByIDs"] = c.KeyResultsByIDs(ctx, []string{"1"}, nil)
+			_, errs["UpdateObjective"] = c.UpdateObjective(ctx, "1", "ON_TRACK", "")
+			_, errs["UpdateKeyResult"] = c.UpdateKeyResult(ctx, "1", "ON_TRACK", 1.0, "")
+
+			for method, err := range errs {
+				for _, sentinel := range sentinels {
+					assert.Equal(t, sentinel == tt.sentinel, errors.Is(err, sentinel), "%s: errors.Is(%v)", method, sentinel)
+				}
+
+				var apiErr *okrforjira.APIError
+				if assert.True(t, errors.As(err, &apiErr), method) {
+					assert.Equal(t, tt.statusCode, apiErr.StatusCode)
+					assert.Equal(t, 2*time.Minute, apiErr.RetryAfter)
+					if assert.NotNil(t, apiErr.ErrorBody) {
+						assert.Equal(t, "something went wrong", apiErr.ErrorBody.Message)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("Request metadata and raw body", func(t *testing.T) {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: 502,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`<html>Bad Gateway</html>`)),
+				Header:     make(http.Header),
+			}
+		})
+		c := okrforjira.NewClient(client, token)
+		_, err := c.UpdateKeyResult(ctx, "1", "ON_TRACK", 1.0, "")
+
+		var apiErr *okrforjira.APIError
+		if assert.True(t, errors.As(err, &apiErr)) {
+			assert.Equal(t, 502, apiErr.StatusCode)
+			assert.Equal(t, "POST", apiErr.Method)
+			assert.Equal(t, "/api/v2/api-update/keyResults", apiErr.Endpoint)
+			assert.Equal(t, []byte(`<html>Bad Gateway</html>`), apiErr.Body)
+			assert.Nil(t, apiErr.ErrorBody)
+			assert.Zero(t, apiErr.RetryAfter)
+			assert.Equal(t, "POST /api/v2/api-update/keyResults: error status 502: <html>Bad Gateway</html>", apiErr.Error())
+		}
+	})
+
+	t.Run("Unreadable body", func(t *testing.T) {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: 404,
+				Body:       ioutil.NopCloser(iotest.ErrReader(errors.New("connection reset"))),
+				Header:     make(http.Header),
+			}
+		})
+		c := okrforjira.NewClient(client, token)
+		_, err := c.KeyResultsByIDs(ctx, []string{"1"}, nil)
+
+		assert.ErrorIs(t, err, okrforjira.ErrNotFound)
+		var apiErr *okrforjira.APIError
+		if assert.True(t, errors.As(err, &apiErr)) {
+			assert.Equal(t, 404, apiErr.StatusCode)
+			assert.Nil(t, apiErr.ErrorBody)
+			assert.Contains(t, apiErr.Error(), "error status 404: failed to read the body: connection reset")
+		}
+	})
+}
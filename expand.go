@@ -0,0 +1,65 @@
+package okrforjira
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// ExpandObject is a type of object that can be expanded in the response of an export.
+type ExpandObject string
+
+// The objects that can be expanded.
+const (
+	ExpandObjectives ExpandObject = "OBJECTIVES"
+	ExpandKeyResults ExpandObject = "KEY_RESULTS"
+	ExpandTeams      ExpandObject = "TEAMS"
+	ExpandPeriods    ExpandObject = "PERIODS"
+	ExpandLabels     ExpandObject = "LABELS"
+)
+
+// ExpandAll returns all the objects that can be expanded.
+func ExpandAll() []ExpandObject {
+	return []ExpandObject{ExpandObjectives, ExpandKeyResults, ExpandTeams, ExpandPeriods, ExpandLabels}
+}
+
+// Validate returns an error if the object cannot be expanded.
+func (o ExpandObject) Validate() error {
+	if !slices.Contains(ExpandAll(), o) {
+		return fmt.Errorf("invalid object %s", o)
+	}
+	return nil
+}
+
+// DedupExpand returns the objects of expand without duplicates, in their original order.
+func DedupExpand(expand []ExpandObject) []ExpandObject {
+	result := make([]ExpandObject, 0, len(expand))
+	for _, object := range expand {
+		if !slices.Contains(result, object) {
+			result = append(result, object)
+		}
+	}
+	return result
+}
+
+func toExpandObjects(expand []string) []ExpandObject {
+	objects := make([]ExpandObject, len(expand))
+	for i, object := range expand {
+		objects[i] = ExpandObject(object)
+	}
+	return objects
+}
+
+// expandQueryParam validates and deduplicates expand, and returns the value of the expand query parameter.
+func expandQueryParam(expand []ExpandObject) (string, error) {
+	expand = DedupExpand(expand)
+	values := make([]string, len(expand))
+	for i, object := range expand {
+		if err := object.Validate(); err != nil {
+			return "", err
+		}
+		values[i] = string(object)
+	}
+	return strings.Join(values, ","), nil
+}
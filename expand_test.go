@@ -0,0 +1,60 @@
+package okrforjira_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupExpand(t *testing.T) {
+	got := okrforjira.DedupExpand([]okrforjira.ExpandObject{
+		okrforjira.ExpandTeams,
+		okrforjira.ExpandLabels,
+		okrforjira.ExpandTeams,
+		okrforjira.ExpandLabels,
+		okrforjira.ExpandPeriods,
+	})
+	assert.Equal(t, []okrforjira.ExpandObject{okrforjira.ExpandTeams, okrforjira.ExpandLabels, okrforjira.ExpandPeriods}, got)
+}
+
+func TestClient_TypedExpand(t *testing.T) {
+	var urls []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		urls = append(urls, req.URL.String())
+		return newJSONResponse(200, `{}`)
+	})
+	c := okrforjira.NewClient(client, token)
+	ctx := context.Background()
+	startDate := time.UnixMilli(1409459200000)
+	deadline := time.UnixMilli(1748647410000)
+
+	_, err := c.ObjectivesByDateExpand(ctx, startDate, deadline, okrforjira.ExpandAll()...)
+	assert.NoError(t, err)
+	_, err = c.ObjectivesByIDsExpand(ctx, []string{"1", "2"}, okrforjira.ExpandTeams, okrforjira.ExpandTeams)
+	assert.NoError(t, err)
+	_, err = c.KeyResultsByDateExpand(ctx, startDate, deadline)
+	assert.NoError(t, err)
+	_, err = c.KeyResultsByIDsExpand(ctx, []string{"3"}, okrforjira.ExpandPeriods, okrforjira.ExpandLabels)
+	assert.NoError(t, err)
+	_, err = c.KeyResultsByIDs(ctx, []string{"3"}, []string{"LABELS", "LABELS"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"https://okr-for-jira-prod.herokuapp.com/api/v2/api-export/objectives/byDate?startDateEpochMilli=1409459200000&deadlineEpochMilli=1748647410000&expand=OBJECTIVES,KEY_RESULTS,TEAMS,PERIODS,LABELS",
+		"https://okr-for-jira-prod.herokuapp.com/api/v2/api-export/objectives/byIds?objectiveIds=1,2&expand=TEAMS",
+		"https://okr-for-jira-prod.herokuapp.com/api/v2/api-export/keyResults/byDate?startDateEpochMilli=1409459200000&deadlineEpochMilli=1748647410000&expand=",
+		"https://okr-for-jira-prod.herokuapp.com/api/v2/api-export/keyResults/byIds?keyResultIds=3&expand=PERIODS,LABELS",
+		"https://okr-for-jira-prod.herokuapp.com/api/v2/api-export/keyResults/byIds?keyResultIds=3&expand=LABELS",
+	}, urls)
+
+	t.Run("Invalid objects are rejected", func(t *testing.T) {
+		_, err := c.ObjectivesByIDs(ctx, []string{"1"}, []string{"KEY_RESULT"})
+		assert.EqualError(t, err, "failed to get the obectives by ids: invalid object KEY_RESULT")
+		_, err = c.KeyResultsByIDsExpand(ctx, []string{"1"}, okrforjira.ExpandObject("TEAM"))
+		assert.EqualError(t, err, "failed to get the key results by ids: invalid object TEAM")
+	})
+}
@@ -0,0 +1,173 @@
+package okrforjira
+
+// Index is an indexed view of a Response with lookups by ID and key,
+// and resolution of the references between the objects.
+type Index struct {
+	okrs            map[string]*OKR
+	okrsByKey       map[string]*OKR
+	keyResults      map[string]*KeyResult
+	keyResultsByKey map[string]*KeyResult
+	teams           map[string]*Team
+	periods         map[string]*Period
+	labels          map[string]*Label
+}
+
+// NewIndex builds an index of the response.
+// The index points to the elements of the slices of the response,
+// which must not be modified while the index is used.
+func NewIndex(r Response) *Index {
+	idx := &Index{
+		okrs:            make(map[string]*OKR, len(r.OKRs)),
+		okrsByKey:       make(map[string]*OKR, len(r.OKRs)),
+		keyResults:      make(map[string]*KeyResult, len(r.KeyResults)),
+		keyResultsByKey: make(map[string]*KeyResult, len(r.KeyResults)),
+		teams:           make(map[string]*Team, len(r.Teams)),
+		periods:         make(map[string]*Period, len(r.Periods)),
+		labels:          make(map[string]*Label, len(r.Labels)),
+	}
+	for i := range r.OKRs {
+		okr := &r.OKRs[i]
+		idx.okrs[okr.ID] = okr
+		if okr.Key != "" {
+			idx.okrsByKey[okr.Key] = okr
+		}
+	}
+	for i := range r.KeyResults {
+		kr := &r.KeyResults[i]
+		idx.keyResults[kr.ID] = kr
+		if kr.Key != "" {
+			idx.keyResultsByKey[kr.Key] = kr
+		}
+	}
+	for i := range r.Teams {
+		idx.teams[r.Teams[i].ID] = &r.Teams[i]
+	}
+	for i := range r.Periods {
+		idx.periods[r.Periods[i].ID] = &r.Periods[i]
+	}
+	for i := range r.Labels {
+		idx.labels[r.Labels[i].ID] = &r.Labels[i]
+	}
+	return idx
+}
+
+// OKR returns the objective with the given ID.
+func (idx *Index) OKR(id string) (*OKR, bool) {
+	okr, ok := idx.okrs[id]
+	return okr, ok
+}
+
+// OKRByKey returns the objective with the given key, e.g. "O-2".
+func (idx *Index) OKRByKey(key string) (*OKR, bool) {
+	okr, ok := idx.okrsByKey[key]
+	return okr, ok
+}
+
+// KeyResult returns the key result with the given ID.
+func (idx *Index) KeyResult(id string) (*KeyResult, bool) {
+	kr, ok := idx.keyResults[id]
+	return kr, ok
+}
+
+// KeyResultByKey returns the key result with the given key, e.g. "KR-8".
+func (idx *Index) KeyResultByKey(key string) (*KeyResult, bool) {
+	kr, ok := idx.keyResultsByKey[key]
+	return kr, ok
+}
+
+// LookupOKR returns the objective with the given ID or, failing that, key.
+func (idx *Index) LookupOKR(ref string) (*OKR, bool) {
+	if okr, ok := idx.OKR(ref); ok {
+		return okr, true
+	}
+	return idx.OKRByKey(ref)
+}
+
+// LookupKeyResult returns the key result with the given ID or, failing
+// that, key.
+func (idx *Index) LookupKeyResult(ref string) (*KeyResult, bool) {
+	if kr, ok := idx.KeyResult(ref); ok {
+		return kr, true
+	}
+	return idx.KeyResultByKey(ref)
+}
+
+// Team returns the team with the given ID.
+func (idx *Index) Team(id string) (*Team, bool) {
+	team, ok := idx.teams[id]
+	return team, ok
+}
+
+// Period returns the period with the given ID.
+func (idx *Index) Period(id string) (*Period, bool) {
+	period, ok := idx.periods[id]
+	return period, ok
+}
+
+// Label returns the label with the given ID.
+func (idx *Index) Label(id string) (*Label, bool) {
+	label, ok := idx.labels[id]
+	return label, ok
+}
+
+// Entity is an objective or a key result, i.e. an OKR or a KeyResult.
+type Entity interface {
+	parentObjectiveID() string
+	teamIDs() []string
+	labelIDs() []string
+	periodAliasID() string
+}
+
+func (o OKR) parentObjectiveID() string { return o.ParentObjectiveID }
+func (o OKR) teamIDs() []string         { return o.TeamIDs }
+func (o OKR) labelIDs() []string        { return o.LabelIDs }
+func (o OKR) periodAliasID() string     { return o.PeriodAliasID }
+
+func (kr KeyResult) parentObjectiveID() string { return kr.ParentObjectiveID }
+func (kr KeyResult) teamIDs() []string         { return kr.TeamIDs }
+func (kr KeyResult) labelIDs() []string        { return kr.LabelIDs }
+func (kr KeyResult) periodAliasID() string     { return kr.PeriodAliasID }
+
+// The resolvers below return the objects of the response referenced by an
+// objective or a key result. References to objects that are not part of the
+// response, e.g. because they were not expanded, are skipped.
+
+// TeamsOf returns the teams of the objective or key result.
+func (idx *Index) TeamsOf(e Entity) []*Team {
+	return resolve(idx.teams, e.teamIDs())
+}
+
+// LabelsOf returns the labels of the objective or key result.
+func (idx *Index) LabelsOf(e Entity) []*Label {
+	return resolve(idx.labels, e.labelIDs())
+}
+
+// PeriodOf returns the period of the objective or key result.
+func (idx *Index) PeriodOf(e Entity) (*Period, bool) {
+	return idx.Period(e.periodAliasID())
+}
+
+// ParentOf returns the parent objective of the objective or key result.
+func (idx *Index) ParentOf(e Entity) (*OKR, bool) {
+	return idx.OKR(e.parentObjectiveID())
+}
+
+// KeyResultsOf returns the key results of the objective.
+func (idx *Index) KeyResultsOf(okr OKR) []*KeyResult {
+	return resolve(idx.keyResults, okr.KRIDs)
+}
+
+// ChildrenOf returns the child objectives of the objective.
+func (idx *Index) ChildrenOf(okr OKR) []*OKR {
+	return resolve(idx.okrs, okr.ChildObjectiveIDs)
+}
+
+func resolve[T any](objects map[string]*T, ids []string) []*T {
+	result := make([]*T, 0, len(ids))
+	for _, id := range ids {
+		if object, ok := objects[id]; ok {
+			result = append(result, object)
+		}
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package okrforjira_test
+
+import (
+	"testing"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestResponse returns a small response with a parent objective O-1,
+// a child objective O-2 and their key results.
+func newTestResponse() okrforjira.Response {
+	return okrforjira.Response{
+		OKRs: []okrforjira.OKR{
+			{
+				ID:                "o1",
+				Key:               "O-1",
+				TeamIDs:           []string{"t1", "t2"},
+				LabelIDs:          []string{"l1"},
+				KRIDs:             []string{"kr1"},
+				ChildObjectiveIDs: []string{"o2", "missing"},
+				PeriodAliasID:     "p1",
+			},
+			{
+				ID:                "o2",
+				Key:               "O-2",
+				ParentObjectiveID: "o1",
+				KRIDs:             []string{"kr2", "kr3"},
+				PeriodAliasID:     "p1",
+			},
+		},
+		KeyResults: []okrforjira.KeyResult{
+			{ID: "kr1", Key: "KR-1", ParentObjectiveID: "o1", LabelIDs: []string{"l2"}, TeamIDs: []string{"t2"}},
+			{ID: "kr2", Key: "KR-2", ParentObjectiveID: "o2", PeriodAliasID: "p1"},
+			{ID: "kr3", Key: "KR-3", ParentObjectiveID: "o2"},
+		},
+		Teams: []okrforjira.Team{
+			{ID: "t1", Name: "Research"},
+			{ID: "t2", Name: "Operations"},
+		},
+		Periods: []okrforjira.Period{
+			{ID: "p1", Name: "Q1 Y2021"},
+		},
+		Labels: []okrforjira.Label{
+			{ID: "l1", Name: "strategic"},
+			{ID: "l2", Name: "tech"},
+		},
+	}
+}
+
+func TestIndex(t *testing.T) {
+	r := newTestResponse()
+	idx := okrforjira.NewIndex(r)
+
+	t.Run("Lookups", func(t *testing.T) {
+		okr, ok := idx.OKR("o2")
+		assert.True(t, ok)
+		assert.Equal(t, "O-2", okr.Key)
+		okr, ok = idx.OKRByKey("O-1")
+		assert.True(t, ok)
+		assert.Equal(t, "o1", okr.ID)
+		kr, ok := idx.KeyResultByKey("KR-3")
+		assert.True(t, ok)
+		assert.Equal(t, "kr3", kr.ID)
+		kr, ok = idx.KeyResult("kr1")
+		assert.True(t, ok)
+		assert.Equal(t, "KR-1", kr.Key)
+		team, ok := idx.Team("t1")
+		assert.True(t, ok)
+		assert.Equal(t, "Research", team.Name)
+		period, ok := idx.Period("p1")
+		assert.True(t, ok)
+		assert.Equal(t, "Q1 Y2021", period.Name)
+		label, ok := idx.Label("l2")
+		assert.True(t, ok)
+		assert.Equal(t, "tech", label.Name)
+
+		_, ok = idx.OKR("missing")
+		assert.False(t, ok)
+		_, ok = idx.KeyResultByKey("KR-99")
+		assert.False(t, ok)
+	})
+
+	t.Run("Lookups by ID or key", func(t *testing.T) {
+		okr, ok := idx.LookupOKR("o2")
+		assert.True(t, ok)
+		assert.Equal(t, "O-2", okr.Key)
+		okr, ok = idx.LookupOKR("O-1")
+		assert.True(t, ok)
+		assert.Equal(t, "o1", okr.ID)
+		kr, ok := idx.LookupKeyResult("kr1")
+		assert.True(t, ok)
+		assert.Equal(t, "KR-1", kr.Key)
+		kr, ok = idx.LookupKeyResult("KR-3")
+		assert.True(t, ok)
+		assert.Equal(t, "kr3", kr.ID)
+
+		_, ok = idx.LookupOKR("KR-1")
+		assert.False(t, ok)
+		_, ok = idx.LookupKeyResult("o1")
+		assert.False(t, ok)
+	})
+
+	t.Run("Resolvers", func(t *testing.T) {
+		o1, o2 := r.OKRs[0], r.OKRs[1]
+		kr1, kr2 := r.KeyResults[0], r.KeyResults[1]
+
+		assert.Equal(t, []*okrforjira.Team{&r.Teams[0], &r.Teams[1]}, idx.TeamsOf(o1))
+		assert.Equal(t, []*okrforjira.Team{&r.Teams[1]}, idx.TeamsOf(kr1))
+		assert.Equal(t, []*okrforjira.Label{&r.Labels[0]}, idx.LabelsOf(o1))
+		assert.Equal(t, []*okrforjira.Label{&r.Labels[1]}, idx.LabelsOf(kr1))
+		assert.Equal(t, []*okrforjira.KeyResult{&r.KeyResults[1], &r.KeyResults[2]}, idx.KeyResultsOf(o2))
+		// References to objects missing from the response are skipped.
+		assert.Equal(t, []*okrforjira.OKR{&r.OKRs[1]}, idx.ChildrenOf(o1))
+
+		period, ok := idx.PeriodOf(o1)
+		assert.True(t, ok)
+		assert.Equal(t, &r.Periods[0], period)
+		_, ok = idx.PeriodOf(kr1)
+		assert.False(t, ok)
+		period, ok = idx.PeriodOf(kr2)
+		assert.True(t, ok)
+		assert.Equal(t, &r.Periods[0], period)
+
+		parent, ok := idx.ParentOf(kr2)
+		assert.True(t, ok)
+		assert.Equal(t, &r.OKRs[1], parent)
+		parent, ok = idx.ParentOf(o2)
+		assert.True(t, ok)
+		assert.Equal(t, &r.OKRs[0], parent)
+		_, ok = idx.ParentOf(o1)
+		assert.False(t, ok)
+	})
+}
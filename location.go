@@ -0,0 +1,85 @@
+package okrforjira
+
+import "time"
+
+// WithLocation sets the location into which all the dates decoded by the
+// client are converted, e.g. to report them in the time zone of the company.
+// By default the dates keep the offset returned by the server.
+func WithLocation(loc *time.Location) Option {
+	return func(c *config) {
+		c.location = loc
+	}
+}
+
+// In returns a copy of the response whose dates are converted into loc.
+// Zero dates are left unchanged.
+func (r Response) In(loc *time.Location) Response {
+	converted := r
+	converted.OKRs = convertAll(r.OKRs, func(o OKR) OKR { return o.in(loc) })
+	converted.KeyResults = convertAll(r.KeyResults, func(kr KeyResult) KeyResult { return kr.in(loc) })
+	converted.Periods = convertAll(r.Periods, func(p Period) Period { return p.in(loc) })
+	return converted
+}
+
+func convertAll[T any](items []T, convert func(T) T) []T {
+	if items == nil {
+		return nil
+	}
+	converted := make([]T, len(items))
+	for i, item := range items {
+		converted[i] = convert(item)
+	}
+	return converted
+}
+
+func (o OKR) in(loc *time.Location) OKR {
+	o.Created = timeIn(o.Created, loc)
+	o.StartDate = timeIn(o.StartDate, loc)
+	o.Deadline = timeIn(o.Deadline, loc)
+	o.LatestUpdate = o.LatestUpdate.in(loc)
+	return o
+}
+
+func (kr KeyResult) in(loc *time.Location) KeyResult {
+	kr.Created = timeIn(kr.Created, loc)
+	kr.StartDate = timeIn(kr.StartDate, loc)
+	kr.Deadline = timeIn(kr.Deadline, loc)
+	kr.LatestUpdate = kr.LatestUpdate.in(loc)
+	return kr
+}
+
+func (u Update) in(loc *time.Location) Update {
+	u.Created = timeIn(u.Created, loc)
+	return u
+}
+
+func (p Period) in(loc *time.Location) Period {
+	p.StartDate = timeIn(p.StartDate, loc)
+	p.Deadline = timeIn(p.Deadline, loc)
+	return p
+}
+
+func timeIn(t time.Time, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(loc)
+}
+
+// inLocation wraps the callbacks of h so that the dates of the objects are
+// converted into loc before they are passed to h.
+func (h StreamHandler) inLocation(loc *time.Location) StreamHandler {
+	if h.OKR != nil {
+		fn := h.OKR
+		h.OKR = func(o OKR) error { return fn(o.in(loc)) }
+	}
+	if h.KeyResult != nil {
+		fn := h.KeyResult
+		h.KeyResult = func(kr KeyResult) error { return fn(kr.in(loc)) }
+	}
+	if h.Period != nil {
+		fn := h.Period
+		h.Period = func(p Period) error { return fn(p.in(loc)) }
+	}
+	return h
+}
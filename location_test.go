@@ -0,0 +1,83 @@
+package okrforjira_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponse_In(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	date := time.Date(2021, time.March, 31, 23, 59, 59, 0, time.UTC)
+	r := okrforjira.Response{
+		OKRs:       []okrforjira.OKR{{ID: "o1", Created: date, LatestUpdate: okrforjira.Update{Created: date}}},
+		KeyResults: []okrforjira.KeyResult{{ID: "kr1", StartDate: date, Deadline: date}},
+		Periods:    []okrforjira.Period{{ID: "p1", StartDate: date}},
+	}
+
+	got := r.In(loc)
+	assert.Equal(t, loc, got.OKRs[0].Created.Location())
+	assert.True(t, got.OKRs[0].Created.Equal(date))
+	assert.Equal(t, loc, got.OKRs[0].LatestUpdate.Created.Location())
+	assert.True(t, got.OKRs[0].StartDate.IsZero())
+	assert.Equal(t, loc, got.KeyResults[0].StartDate.Location())
+	assert.Equal(t, loc, got.KeyResults[0].Deadline.Location())
+	assert.Equal(t, loc, got.Periods[0].StartDate.Location())
+	assert.True(t, got.Periods[0].Deadline.IsZero())
+
+	// The original response is not modified.
+	assert.Equal(t, time.UTC, r.OKRs[0].Created.Location())
+	assert.Equal(t, time.UTC, r.Periods[0].StartDate.Location())
+}
+
+func TestClient_WithLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	ctx := context.Background()
+
+	t.Run("Export", func(t *testing.T) {
+		body := string(readFixture(t, "objectives_by_ids.json"))
+		c := okrforjira.NewClient(NewTestClient(func(req *http.Request) *http.Response {
+			return newJSONResponse(200, body)
+		}), token, okrforjira.WithLocation(loc))
+		got, err := c.ObjectivesByIDs(ctx, []string{"1"}, nil)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, got.OKRs)
+		for _, o := range got.OKRs {
+			assert.Equal(t, loc, o.Created.Location())
+		}
+		for _, p := range got.Periods {
+			assert.Equal(t, loc, p.Deadline.Location())
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		body := string(readFixture(t, "key_results_by_ids.json"))
+		c := okrforjira.NewClient(NewTestClient(func(req *http.Request) *http.Response {
+			return newJSONResponse(200, body)
+		}), token, okrforjira.WithLocation(loc))
+		var count int
+		err := c.StreamKeyResultsByIDs(ctx, []string{"1"}, okrforjira.StreamHandler{
+			KeyResult: func(kr okrforjira.KeyResult) error {
+				count++
+				assert.Equal(t, loc, kr.Created.Location())
+				return nil
+			},
+		})
+		assert.NoError(t, err)
+		assert.NotZero(t, count)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		body := string(readFixture(t, "update_objective.json"))
+		c := okrforjira.NewClient(NewTestClient(func(req *http.Request) *http.Response {
+			return newJSONResponse(200, body)
+		}), token, okrforjira.WithLocation(loc))
+		got, err := c.UpdateObjective(ctx, "1", okrforjira.StatusOnTrack, "")
+		assert.NoError(t, err)
+		assert.Equal(t, loc, got.Created.Location())
+	})
+}
@@ -0,0 +1,76 @@
+package okrforjira
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+var keyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]*-[0-9]+$`)
+
+// IsKey reports whether ref is the key of an objective or a key result,
+// e.g. "O-3" or "KR-8", rather than an ID.
+func IsKey(ref string) bool {
+	return keyPattern.MatchString(ref)
+}
+
+// Lookup returns the objectives and key results referenced by refs, which
+// are either IDs or keys, see IsKey. The export endpoints only accept IDs, so
+// the keys are looked up in the objectives and key results with a start date
+// or deadline in search. The references that are not found are ignored;
+// NewIndex can be used on the response to check them.
+func (c *Client) Lookup(ctx context.Context, refs []string, search DateRange) (Response, error) {
+	var ids, keys []string
+	for _, ref := range refs {
+		if IsKey(ref) {
+			keys = append(keys, ref)
+		} else {
+			ids = append(ids, ref)
+		}
+	}
+
+	var responses []Response
+	if len(ids) > 0 {
+		okrs, err := c.ObjectivesByIDsExpand(ctx, ids)
+		if err != nil {
+			return Response{}, fmt.Errorf("failed to look up %v: %w", ids, err)
+		}
+		krs, err := c.KeyResultsByIDsExpand(ctx, ids)
+		if err != nil {
+			return Response{}, fmt.Errorf("failed to look up %v: %w", ids, err)
+		}
+		responses = append(responses, okrs, krs)
+	}
+	if len(keys) > 0 {
+		if search.StartDate.IsZero() || search.Deadline.IsZero() {
+			return Response{}, fmt.Errorf("failed to look up %v: a date range is required to look up keys", keys)
+		}
+		okrs, err := c.ObjectivesByDateExpand(ctx, search.StartDate, search.Deadline)
+		if err != nil {
+			return Response{}, fmt.Errorf("failed to look up %v: %w", keys, err)
+		}
+		krs, err := c.KeyResultsByDateExpand(ctx, search.StartDate, search.Deadline)
+		if err != nil {
+			return Response{}, fmt.Errorf("failed to look up %v: %w", keys, err)
+		}
+		responses = append(responses, okrs, krs)
+	}
+
+	wanted := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		wanted[ref] = true
+	}
+	merged := MergeResponses(responses...)
+	var result Response
+	for _, o := range merged.OKRs {
+		if wanted[o.ID] || wanted[o.Key] {
+			result.OKRs = append(result.OKRs, o)
+		}
+	}
+	for _, kr := range merged.KeyResults {
+		if wanted[kr.ID] || wanted[kr.Key] {
+			result.KeyResults = append(result.KeyResults, kr)
+		}
+	}
+	return result, nil
+}
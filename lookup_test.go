@@ -0,0 +1,65 @@
+package okrforjira_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/grandper/okrforjira/okrforjiratest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsKey(t *testing.T) {
+	for _, key := range []string{"O-1", "KR-8", "OKR2-123"} {
+		assert.True(t, okrforjira.IsKey(key), key)
+	}
+	for _, ref := range []string{"", "5fda249d289742000406b3e4", "kr-8", "KR-", "KR8", "-8"} {
+		assert.False(t, okrforjira.IsKey(ref), ref)
+	}
+}
+
+func TestClient_Lookup(t *testing.T) {
+	s := okrforjiratest.NewServer(okrforjiratest.DefaultSeed())
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	search := okrforjira.DateRange{
+		StartDate: time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Deadline:  time.Date(2022, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+	keys := func(r okrforjira.Response) []string {
+		var keys []string
+		for _, o := range r.OKRs {
+			keys = append(keys, o.Key)
+		}
+		for _, kr := range r.KeyResults {
+			keys = append(keys, kr.Key)
+		}
+		return keys
+	}
+
+	t.Run("IDs and keys", func(t *testing.T) {
+		got, err := c.Lookup(ctx, []string{"KR-3", "o1", "kr4", "O-2", "KR-99", "unknown"}, search)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"O-1", "O-2", "KR-3", "KR-4"}, keys(got))
+	})
+
+	t.Run("Only IDs do not need a range", func(t *testing.T) {
+		got, err := c.Lookup(ctx, []string{"kr1"}, okrforjira.DateRange{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"KR-1"}, keys(got))
+	})
+
+	t.Run("Keys need a range", func(t *testing.T) {
+		_, err := c.Lookup(ctx, []string{"KR-1"}, okrforjira.DateRange{})
+		assert.EqualError(t, err, "failed to look up [KR-1]: a date range is required to look up keys")
+	})
+
+	t.Run("Keys outside of the range", func(t *testing.T) {
+		q1 := okrforjira.DateRange{StartDate: search.StartDate, Deadline: time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)}
+		got, err := c.Lookup(ctx, []string{"KR-1", "KR-3"}, q1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"KR-1"}, keys(got))
+	})
+}
@@ -0,0 +1,115 @@
+package okrexport
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grandper/okrforjira"
+)
+
+// Column is a column of a spreadsheet.
+type Column struct {
+	// Name identifies the column in ParseColumns.
+	Name string
+	// Header is the first cell of the column.
+	Header string
+	// Value returns the cell of the row: a string, a float64, a time.Time
+	// or nil for an empty cell.
+	Value func(Row) interface{}
+}
+
+// The columns known by ParseColumns.
+var (
+	ColumnType              = Column{"type", "Type", func(r Row) interface{} { return kindName(r) }}
+	ColumnID                = Column{"id", "ID", func(r Row) interface{} { return r.ID }}
+	ColumnKey               = Column{"key", "Key", func(r Row) interface{} { return r.Key }}
+	ColumnName              = Column{"name", "Name", func(r Row) interface{} { return r.Name }}
+	ColumnObjective         = Column{"objective", "Objective", func(r Row) interface{} { return r.Objective }}
+	ColumnOwner             = Column{"owner", "Owner", func(r Row) interface{} { return r.Owner }}
+	ColumnCollaborators     = Column{"collaborators", "Collaborators", func(r Row) interface{} { return strings.Join(r.Collaborators, ", ") }}
+	ColumnStatus            = Column{"status", "Status", func(r Row) interface{} { return string(r.Status) }}
+	ColumnValue             = Column{"value", "Value", func(r Row) interface{} { return nullValue(r) }}
+	ColumnUnit              = Column{"unit", "Unit", func(r Row) interface{} { return r.Unit }}
+	ColumnProgress          = Column{"progress", "Progress (%)", func(r Row) interface{} { return r.PercentDone }}
+	ColumnStartDate         = Column{"start", "Start date", func(r Row) interface{} { return timeValue(r.StartDate) }}
+	ColumnDeadline          = Column{"deadline", "Deadline", func(r Row) interface{} { return timeValue(r.Deadline) }}
+	ColumnPeriod            = Column{"period", "Period", func(r Row) interface{} { return r.Period }}
+	ColumnTeams             = Column{"teams", "Teams", func(r Row) interface{} { return strings.Join(r.Teams, ", ") }}
+	ColumnLabels            = Column{"labels", "Labels", func(r Row) interface{} { return strings.Join(r.Labels, ", ") }}
+	ColumnUpdated           = Column{"updated", "Updated", func(r Row) interface{} { return timeValue(r.Updated) }}
+	ColumnUpdateDescription = Column{"update-description", "Update description", func(r Row) interface{} { return r.UpdateDescription }}
+	ColumnDescription       = Column{"description", "Description", func(r Row) interface{} { return r.Description }}
+	ColumnLink              = Column{"link", "Link", func(r Row) interface{} { return r.Link }}
+)
+
+// AllColumns returns all the columns known by ParseColumns.
+func AllColumns() []Column {
+	return []Column{
+		ColumnType, ColumnID, ColumnKey, ColumnName, ColumnObjective, ColumnOwner,
+		ColumnCollaborators, ColumnStatus, ColumnValue, ColumnUnit, ColumnProgress,
+		ColumnStartDate, ColumnDeadline, ColumnPeriod, ColumnTeams, ColumnLabels,
+		ColumnUpdated, ColumnUpdateDescription, ColumnDescription, ColumnLink,
+	}
+}
+
+// DefaultColumns returns the columns used when none are given.
+func DefaultColumns() []Column {
+	return []Column{
+		ColumnType, ColumnKey, ColumnName, ColumnObjective, ColumnOwner,
+		ColumnStatus, ColumnValue, ColumnUnit, ColumnProgress,
+		ColumnStartDate, ColumnDeadline, ColumnPeriod, ColumnTeams, ColumnLabels,
+	}
+}
+
+// ParseColumns returns the columns with the given names, e.g. "key" or
+// "update-description", see AllColumns.
+func ParseColumns(names []string) ([]Column, error) {
+	columns := make([]Column, 0, len(names))
+	for _, name := range names {
+		column, ok := findColumn(strings.ToLower(strings.TrimSpace(name)))
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func findColumn(name string) (Column, bool) {
+	for _, column := range AllColumns() {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return Column{}, false
+}
+
+func kindName(r Row) string {
+	switch r.Kind {
+	case okrforjira.EntityObjective:
+		return "Objective"
+	case okrforjira.EntityKeyResult:
+		return "Key result"
+	}
+	return ""
+}
+
+func nullValue(r Row) interface{} {
+	if v, ok := r.Value.Get(); ok {
+		return v
+	}
+	return nil
+}
+
+func timeValue(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// isDate reports whether t is at midnight, i.e. is a date without time.
+func isDate(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
@@ -0,0 +1,62 @@
+package okrexport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/grandper/okrforjira"
+)
+
+// The layouts of the dates in CSV files.
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// WriteCSV writes the rows of the response, see Rows, as CSV with a header
+// line. The default columns are used if columns is empty. The numbers use a
+// dot as decimal separator, and the dates are written as 2006-01-02, or
+// 2006-01-02 15:04:05 if they are not at midnight.
+func WriteCSV(w io.Writer, r okrforjira.Response, columns []Column) error {
+	if len(columns) == 0 {
+		columns = DefaultColumns()
+	}
+	cw := csv.NewWriter(w)
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = column.Header
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+	for _, row := range Rows(r) {
+		for i, column := range columns {
+			record[i] = formatCSV(column.Value(row))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatCSV(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if isDate(v) {
+			return v.Format(dateLayout)
+		}
+		return v.Format(dateTimeLayout)
+	}
+	return fmt.Sprint(v)
+}
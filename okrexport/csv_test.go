@@ -0,0 +1,47 @@
+package okrexport_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/grandper/okrforjira/okrexport"
+	"github.com/grandper/okrforjira/okrforjiratest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := okrexport.WriteCSV(&buf, okrforjiratest.DefaultSeed(), nil)
+	assert.NoError(t, err)
+	want := "Type,Key,Name,Objective,Owner,Status,Value,Unit,Progress (%),Start date,Deadline,Period,Teams,Labels\n" +
+		"Objective,O-1,Grow the user base,,account-1,ON_TRACK,,,31.666666666666668,2022-01-01,2022-03-31 23:59:59,Q1 2022,Product team,growth\n" +
+		"Key result,KR-1,Reach 10000 active users,O-1,account-1,ON_TRACK,4000,users,25,2022-01-01,2022-03-31 23:59:59,Q1 2022,Product team,growth\n" +
+		"Key result,KR-2,Launch the referral program,O-1,account-2,,,,50,2022-01-01,2022-03-31 23:59:59,Q1 2022,Product team,\n" +
+		"Objective,O-2,Improve the reliability,,account-2,,,,0,2022-04-01,2022-06-30 23:59:59,Q2 2022,Operations,quality\n" +
+		"Key result,KR-3,Reduce the number of incidents,O-2,account-2,,,incidents,0,2022-04-01,2022-06-30 23:59:59,Q2 2022,Operations,quality\n" +
+		"Objective,O-3,Improve the onboarding,O-1,account-3,,,,20,2022-01-01,2022-03-31 23:59:59,Q1 2022,Product team,\n" +
+		"Key result,KR-4,Increase the onboarding conversion,O-3,account-3,AT_RISK,33,%,20,2022-01-01,2022-03-31 23:59:59,Q1 2022,Product team,\n"
+	assert.Equal(t, want, buf.String())
+}
+
+func TestWriteCSV_Columns(t *testing.T) {
+	r := okrforjira.Response{
+		KeyResults: []okrforjira.KeyResult{{
+			Key:            "KR-1",
+			Name:           "Ship, then \"measure\"",
+			OwnerAccountID: "account-1",
+			LatestUpdate: okrforjira.Update{
+				Created:     time.Date(2022, time.February, 1, 10, 30, 0, 0, time.UTC),
+				Description: "Two lines\nof text",
+			},
+		}},
+	}
+	columns := []okrexport.Column{okrexport.ColumnKey, okrexport.ColumnName, okrexport.ColumnValue, okrexport.ColumnUpdated, okrexport.ColumnUpdateDescription}
+	var buf bytes.Buffer
+	assert.NoError(t, okrexport.WriteCSV(&buf, r, columns))
+	want := "Key,Name,Value,Updated,Update description\n" +
+		"KR-1,\"Ship, then \"\"measure\"\"\",,2022-02-01 10:30:00,\"Two lines\nof text\"\n"
+	assert.Equal(t, want, buf.String())
+}
@@ -0,0 +1,158 @@
+// Package okrexport flattens the objectives and key results of a response
+// into rows, and writes them as CSV or XLSX spreadsheets.
+package okrexport
+
+import (
+	"time"
+
+	"github.com/grandper/okrforjira"
+)
+
+// Row is an objective or a key result with the names of its parent, teams,
+// labels and period instead of their IDs.
+type Row struct {
+	Kind          okrforjira.EntityKind
+	ID            string
+	Key           string
+	Name          string
+	Link          string
+	Description   string
+	Owner         string
+	Collaborators []string
+	// Objective is the key of the parent objective, or its ID if the parent
+	// is not in the response.
+	Objective   string
+	Status      okrforjira.Status
+	Value       okrforjira.NullFloat64
+	Unit        string
+	PercentDone float64
+	StartDate   time.Time
+	Deadline    time.Time
+	// Updated is the creation date of the latest update.
+	Updated           time.Time
+	UpdateDescription string
+	// PeriodID and Period are the ID and the name of the period. A key result
+	// without period has the period of its parent objective.
+	PeriodID string
+	Period   string
+	Teams    []string
+	Labels   []string
+}
+
+// Rows returns the rows of the response: each objective is followed by its
+// key results, and the key results of objectives missing from the response
+// come last. The teams, labels and periods missing from the response, e.g.
+// because they were not expanded, have an empty name.
+func Rows(r okrforjira.Response) []Row {
+	idx := okrforjira.NewIndex(r)
+	byParent := make(map[string][]okrforjira.KeyResult)
+	for _, kr := range r.KeyResults {
+		byParent[kr.ParentObjectiveID] = append(byParent[kr.ParentObjectiveID], kr)
+	}
+
+	rows := make([]Row, 0, len(r.OKRs)+len(r.KeyResults))
+	for _, o := range r.OKRs {
+		rows = append(rows, objectiveRow(idx, o))
+		for _, kr := range byParent[o.ID] {
+			rows = append(rows, keyResultRow(idx, kr))
+		}
+		delete(byParent, o.ID)
+	}
+	for _, kr := range r.KeyResults {
+		if _, ok := byParent[kr.ParentObjectiveID]; ok {
+			rows = append(rows, keyResultRow(idx, kr))
+		}
+	}
+	return rows
+}
+
+func objectiveRow(idx *okrforjira.Index, o okrforjira.OKR) Row {
+	return Row{
+		Kind:              okrforjira.EntityObjective,
+		ID:                o.ID,
+		Key:               o.Key,
+		Name:              o.Name,
+		Link:              o.Link,
+		Description:       o.Description,
+		Owner:             o.OwnerAccountID,
+		Collaborators:     o.CollaboratorAccountIDs,
+		Objective:         parentKey(idx, o.ParentObjectiveID),
+		Status:            o.LatestUpdate.Status,
+		Value:             o.LatestUpdate.Value,
+		PercentDone:       o.PercentDone,
+		StartDate:         o.StartDate,
+		Deadline:          o.Deadline,
+		Updated:           o.LatestUpdate.Created,
+		UpdateDescription: o.LatestUpdate.Description,
+		PeriodID:          o.PeriodAliasID,
+		Period:            periodName(idx, o.PeriodAliasID),
+		Teams:             teamNames(idx, o),
+		Labels:            labelNames(idx, o),
+	}
+}
+
+func keyResultRow(idx *okrforjira.Index, kr okrforjira.KeyResult) Row {
+	unit := kr.Unit.Symbol
+	if unit == "" {
+		unit = kr.Unit.Name
+	}
+	periodID := kr.PeriodAliasID
+	if periodID == "" {
+		if parent, ok := idx.ParentOf(kr); ok {
+			periodID = parent.PeriodAliasID
+		}
+	}
+	return Row{
+		Kind:              okrforjira.EntityKeyResult,
+		ID:                kr.ID,
+		Key:               kr.Key,
+		Name:              kr.Name,
+		Link:              kr.Link,
+		Description:       kr.Description,
+		Owner:             kr.OwnerAccountID,
+		Collaborators:     kr.CollaboratorAccountIds,
+		Objective:         parentKey(idx, kr.ParentObjectiveID),
+		Status:            kr.LatestUpdate.Status,
+		Value:             kr.LatestUpdate.Value,
+		Unit:              unit,
+		PercentDone:       kr.PercentDone,
+		StartDate:         kr.StartDate,
+		Deadline:          kr.Deadline,
+		Updated:           kr.LatestUpdate.Created,
+		UpdateDescription: kr.LatestUpdate.Description,
+		PeriodID:          periodID,
+		Period:            periodName(idx, periodID),
+		Teams:             teamNames(idx, kr),
+		Labels:            labelNames(idx, kr),
+	}
+}
+
+func parentKey(idx *okrforjira.Index, parentID string) string {
+	if o, ok := idx.OKR(parentID); ok {
+		return o.Key
+	}
+	return parentID
+}
+
+func periodName(idx *okrforjira.Index, id string) string {
+	if p, ok := idx.Period(id); ok {
+		return p.Name
+	}
+	return ""
+}
+
+func teamNames(idx *okrforjira.Index, e okrforjira.Entity) []string {
+	var names []string
+	for _, t := range idx.TeamsOf(e) {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func labelNames(idx *okrforjira.Index, e okrforjira.Entity) []string {
+	var names []string
+	for _, l := range idx.LabelsOf(e) {
+		names = append(names, l.Name)
+	}
+	return names
+}
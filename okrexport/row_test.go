@@ -0,0 +1,90 @@
+package okrexport_test
+
+import (
+	"testing"
+
+	"github.com/grandper/okrforjira"
+	"github.com/grandper/okrforjira/okrexport"
+	"github.com/grandper/okrforjira/okrforjiratest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRows(t *testing.T) {
+	rows := okrexport.Rows(okrforjiratest.DefaultSeed())
+
+	var keys []string
+	for _, row := range rows {
+		keys = append(keys, row.Key)
+	}
+	assert.Equal(t, []string{"O-1", "KR-1", "KR-2", "O-2", "KR-3", "O-3", "KR-4"}, keys)
+
+	o3 := rows[5]
+	assert.Equal(t, okrforjira.EntityObjective, o3.Kind)
+	assert.Equal(t, "O-1", o3.Objective)
+	assert.Equal(t, "account-3", o3.Owner)
+	assert.Equal(t, []string{"account-1"}, o3.Collaborators)
+	assert.Equal(t, "Q1 2022", o3.Period)
+	assert.Equal(t, []string{"Product team"}, o3.Teams)
+
+	kr1 := rows[1]
+	assert.Equal(t, okrforjira.EntityKeyResult, kr1.Kind)
+	assert.Equal(t, "O-1", kr1.Objective)
+	assert.Equal(t, okrforjira.StatusOnTrack, kr1.Status)
+	assert.Equal(t, okrforjira.NewNullFloat64(4000), kr1.Value)
+	assert.Equal(t, "users", kr1.Unit)
+	assert.Equal(t, []string{"growth"}, kr1.Labels)
+	assert.Equal(t, "%", rows[6].Unit)
+}
+
+func TestRows_KeyResultWithoutPeriod(t *testing.T) {
+	r := okrforjiratest.DefaultSeed()
+	for i := range r.KeyResults {
+		if r.KeyResults[i].Key == "KR-3" {
+			r.KeyResults[i].PeriodAliasID = ""
+		}
+	}
+	r.KeyResults = append(r.KeyResults, okrforjira.KeyResult{ID: "kr5", Key: "KR-5", ParentObjectiveID: "missing"})
+
+	rows := okrexport.Rows(r)
+	// KR-3 has the period of its objective O-2.
+	kr3 := rows[4]
+	assert.Equal(t, "KR-3", kr3.Key)
+	assert.Equal(t, "p-q2", kr3.PeriodID)
+	assert.Equal(t, "Q2 2022", kr3.Period)
+
+	kr5 := rows[7]
+	assert.Equal(t, "KR-5", kr5.Key)
+	assert.Empty(t, kr5.PeriodID)
+	assert.Empty(t, kr5.Period)
+}
+
+func TestRows_NotExpanded(t *testing.T) {
+	r := okrforjiratest.DefaultSeed()
+	r.OKRs = r.OKRs[1:2]
+	r.Teams, r.Periods, r.Labels = nil, nil, nil
+
+	rows := okrexport.Rows(r)
+	var keys []string
+	for _, row := range rows {
+		keys = append(keys, row.Key)
+	}
+	// The key results of O-1 and O-3, which are missing, come last.
+	assert.Equal(t, []string{"O-2", "KR-3", "KR-1", "KR-2", "KR-4"}, keys)
+	assert.Equal(t, "o1", rows[2].Objective)
+	assert.Equal(t, "p-q1", rows[2].PeriodID)
+	assert.Empty(t, rows[2].Period)
+	assert.Empty(t, rows[2].Teams)
+}
+
+func TestParseColumns(t *testing.T) {
+	columns, err := okrexport.ParseColumns([]string{"key", " Owner", "UPDATE-DESCRIPTION"})
+	assert.NoError(t, err)
+	var headers []string
+	for _, column := range columns {
+		headers = append(headers, column.Header)
+	}
+	assert.Equal(t, []string{"Key", "Owner", "Update description"}, headers)
+
+	_, err = okrexport.ParseColumns([]string{"key", "budget"})
+	assert.EqualError(t, err, `unknown column "budget"`)
+}
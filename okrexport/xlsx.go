@@ -0,0 +1,215 @@
+package okrexport
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/grandper/okrforjira"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/exp/slices"
+)
+
+// NoPeriodSheet is the name of the sheet of the rows without period.
+const NoPeriodSheet = "No period"
+
+// maxSheetName is the maximum length of a sheet name in Excel.
+const maxSheetName = 31
+
+// The built-in number formats of Excel used for the dates.
+const (
+	numFmtDate     = 14 // m/d/yyyy
+	numFmtDateTime = 22 // m/d/yyyy h:mm
+)
+
+// WriteXLSX writes the rows of the response, see Rows, as an XLSX workbook
+// with a sheet per period, named after the period and ordered by start date.
+// The rows without period, or whose period is not in the response, are in
+// the NoPeriodSheet sheet. The default columns are used if columns is empty.
+func WriteXLSX(w io.Writer, r okrforjira.Response, columns []Column) error {
+	if len(columns) == 0 {
+		columns = DefaultColumns()
+	}
+	sheets := sheetsByPeriod(r, Rows(r))
+	f := excelize.NewFile()
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		return fmt.Errorf("failed to write the XLSX file: %w", err)
+	}
+	for i, s := range sheets {
+		if i == 0 {
+			err = f.SetSheetName(f.GetSheetName(0), s.name)
+		} else {
+			_, err = f.NewSheet(s.name)
+		}
+		if err == nil {
+			err = writeSheet(f, styles, s, columns)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write the sheet %q: %w", s.name, err)
+		}
+	}
+	f.SetActiveSheet(0)
+	if err := f.Write(w); err != nil {
+		return fmt.Errorf("failed to write the XLSX file: %w", err)
+	}
+	return nil
+}
+
+// sheet is a sheet of the workbook.
+type sheet struct {
+	name  string
+	start time.Time
+	rows  []Row
+}
+
+// sheetsByPeriod groups the rows by period. There is always at least one
+// sheet, since a workbook cannot be empty.
+func sheetsByPeriod(r okrforjira.Response, rows []Row) []*sheet {
+	idx := okrforjira.NewIndex(r)
+	byPeriod := make(map[string]*sheet)
+	var sheets []*sheet
+	for _, row := range rows {
+		id := row.PeriodID
+		p, ok := idx.Period(id)
+		if !ok {
+			id = ""
+		}
+		s, ok := byPeriod[id]
+		if !ok {
+			s = &sheet{name: NoPeriodSheet}
+			if id != "" {
+				s.name, s.start = p.Name, p.StartDate
+			}
+			byPeriod[id] = s
+			sheets = append(sheets, s)
+		}
+		s.rows = append(s.rows, row)
+	}
+	if len(sheets) == 0 {
+		return []*sheet{{name: NoPeriodSheet}}
+	}
+	// The sheets without start date, e.g. NoPeriodSheet, come last.
+	slices.SortStableFunc(sheets, func(a, b *sheet) bool {
+		if a.start.IsZero() || b.start.IsZero() {
+			return !a.start.IsZero() && b.start.IsZero()
+		}
+		return a.start.Before(b.start)
+	})
+	used := make(map[string]bool)
+	for _, s := range sheets {
+		s.name = uniqueSheetName(s.name, used)
+	}
+	return sheets
+}
+
+// uniqueSheetName returns a valid sheet name for name that is not in used,
+// and adds it to used. Sheet names are case insensitive in Excel.
+func uniqueSheetName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	name = strings.Trim(name, "'")
+	if name == "" {
+		name = "Period"
+	}
+	name = truncate(name, maxSheetName)
+	unique := name
+	for i := 2; used[strings.ToLower(unique)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		unique = truncate(name, maxSheetName-len(suffix)) + suffix
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
+
+func truncate(s string, n int) string {
+	for utf8.RuneCountInString(s) > n {
+		_, size := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-size]
+	}
+	return s
+}
+
+// xlsxStyles are the IDs of the styles of the cells.
+type xlsxStyles struct {
+	header   int
+	date     int
+	dateTime int
+}
+
+func newXLSXStyles(f *excelize.File) (xlsxStyles, error) {
+	var s xlsxStyles
+	var err error
+	if s.header, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		return s, err
+	}
+	if s.date, err = f.NewStyle(&excelize.Style{NumFmt: numFmtDate}); err != nil {
+		return s, err
+	}
+	s.dateTime, err = f.NewStyle(&excelize.Style{NumFmt: numFmtDateTime})
+	return s, err
+}
+
+// writeSheet writes the header and the rows of the sheet, with a frozen
+// header and a filter.
+func writeSheet(f *excelize.File, styles xlsxStyles, s *sheet, columns []Column) error {
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column.Header
+	}
+	if err := f.SetSheetRow(s.name, "A1", &header); err != nil {
+		return err
+	}
+	if err := f.SetRowStyle(s.name, 1, 1, styles.header); err != nil {
+		return err
+	}
+	for i, row := range s.rows {
+		for j, column := range columns {
+			cell, err := excelize.CoordinatesToCellName(j+1, i+2)
+			if err != nil {
+				return err
+			}
+			value := column.Value(row)
+			t, isTime := value.(time.Time)
+			if isTime {
+				// Excel dates have no time zone: keep the wall clock.
+				value = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+			}
+			if value == nil {
+				continue
+			}
+			if err := f.SetCellValue(s.name, cell, value); err != nil {
+				return err
+			}
+			if isTime {
+				style := styles.dateTime
+				if isDate(t) {
+					style = styles.date
+				}
+				if err := f.SetCellStyle(s.name, cell, cell, style); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	last, err := excelize.CoordinatesToCellName(len(columns), len(s.rows)+1)
+	if err != nil {
+		return err
+	}
+	if err := f.AutoFilter(s.name, "A1:"+last, nil); err != nil {
+		return err
+	}
+	return f.SetPanes(s.name, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+}
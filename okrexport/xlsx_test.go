@@ -0,0 +1,117 @@
+package okrexport_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/grandper/okrforjira/okrexport"
+	"github.com/grandper/okrforjira/okrforjiratest"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func writeXLSX(t *testing.T, r okrforjira.Response, columns []okrexport.Column) *excelize.File {
+	t.Helper()
+	var buf bytes.Buffer
+	assert.NoError(t, okrexport.WriteXLSX(&buf, r, columns))
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestWriteXLSX(t *testing.T) {
+	r := okrforjiratest.DefaultSeed()
+	r.KeyResults = append(r.KeyResults, okrforjira.KeyResult{ID: "kr5", Key: "KR-5", Name: "Without period"})
+	// The periods are sorted by start date.
+	r.Periods[0], r.Periods[1] = r.Periods[1], r.Periods[0]
+
+	columns := []okrexport.Column{okrexport.ColumnKey, okrexport.ColumnValue, okrexport.ColumnStartDate, okrexport.ColumnUpdated, okrexport.ColumnTeams}
+	f := writeXLSX(t, r, columns)
+	assert.Equal(t, []string{"Q1 2022", "Q2 2022", okrexport.NoPeriodSheet}, f.GetSheetList())
+
+	rows, err := f.GetRows("Q1 2022")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Key", "Value", "Start date", "Updated", "Teams"},
+		{"O-1", "", "01-01-22", "2/1/22 10:00", "Product team"},
+		{"KR-1", "4000", "01-01-22", "2/1/22 10:00", "Product team"},
+		{"KR-2", "", "01-01-22", "", "Product team"},
+		{"O-3", "", "01-01-22", "", "Product team"},
+		{"KR-4", "33", "01-01-22", "2/15/22 10:00", "Product team"},
+	}, rows)
+
+	rows, err = f.GetRows("Q2 2022")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Key", "Value", "Start date", "Updated", "Teams"},
+		{"O-2", "", "04-01-22", "", "Operations"},
+		{"KR-3", "", "04-01-22", "", "Operations"},
+	}, rows)
+
+	rows, err = f.GetRows(okrexport.NoPeriodSheet)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Key", "Value", "Start date", "Updated", "Teams"}, {"KR-5"}}, rows)
+
+	// The values are numbers and dates, not text.
+	cellType, err := f.GetCellType("Q1 2022", "B3")
+	assert.NoError(t, err)
+	assert.NotEqual(t, excelize.CellTypeSharedString, cellType)
+	raw, err := f.GetCellValue("Q1 2022", "C2", excelize.Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "44562", raw)
+}
+
+func TestWriteXLSX_KeyResultWithoutPeriod(t *testing.T) {
+	r := okrforjiratest.DefaultSeed()
+	for i := range r.KeyResults {
+		r.KeyResults[i].PeriodAliasID = ""
+	}
+	f := writeXLSX(t, r, []okrexport.Column{okrexport.ColumnKey})
+	// The key results are in the sheet of their objective.
+	assert.Equal(t, []string{"Q1 2022", "Q2 2022"}, f.GetSheetList())
+	rows, err := f.GetRows("Q2 2022")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Key"}, {"O-2"}, {"KR-3"}}, rows)
+}
+
+func TestWriteXLSX_WallClock(t *testing.T) {
+	paris := time.FixedZone("CET", 3600)
+	r := okrforjira.Response{
+		OKRs: []okrforjira.OKR{{Key: "O-1", StartDate: time.Date(2022, time.January, 1, 0, 0, 0, 0, paris)}},
+	}
+	f := writeXLSX(t, r, []okrexport.Column{okrexport.ColumnStartDate})
+	rows, err := f.GetRows(okrexport.NoPeriodSheet)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Start date"}, {"01-01-22"}}, rows)
+}
+
+func TestWriteXLSX_SheetNames(t *testing.T) {
+	start := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	r := okrforjira.Response{
+		OKRs: []okrforjira.OKR{
+			{Key: "O-1", PeriodAliasID: "p1"},
+			{Key: "O-2", PeriodAliasID: "p2"},
+			{Key: "O-3", PeriodAliasID: "p3"},
+		},
+		Periods: []okrforjira.Period{
+			{ID: "p1", Name: "2022/Q1 [draft]", StartDate: start},
+			{ID: "p2", Name: "2022-Q1 [DRAFT]", StartDate: start.AddDate(0, 0, 1)},
+			{ID: "p3", Name: "A period with a very long name for a sheet", StartDate: start.AddDate(0, 0, 2)},
+		},
+	}
+	f := writeXLSX(t, r, nil)
+	assert.Equal(t, []string{"2022-Q1 -draft-", "2022-Q1 -DRAFT- (2)", "A period with a very long name "}, f.GetSheetList())
+}
+
+func TestWriteXLSX_Empty(t *testing.T) {
+	f := writeXLSX(t, okrforjira.Response{}, nil)
+	assert.Equal(t, []string{okrexport.NoPeriodSheet}, f.GetSheetList())
+	rows, err := f.GetRows(okrexport.NoPeriodSheet)
+	assert.NoError(t, err)
+	assert.Equal(t, "Type", rows[0][0])
+}
@@ -0,0 +1,81 @@
+package okrforjiratest
+
+import (
+	"net/http"
+	"time"
+)
+
+// Fault is a failure injected in the responses of a Server,
+// see InjectFault.
+type Fault struct {
+	// Method is the method of the affected requests, or any method if empty.
+	Method string
+	// Path is the path of the affected requests, e.g. ObjectivesByIDsPath,
+	// or any path if empty.
+	Path string
+	// StatusCode is the status code of the response. If 0, the request is
+	// served normally after the latency.
+	StatusCode int
+	// Body is the body of the response. If empty, an error body like the
+	// ones of the API is sent.
+	Body string
+	// Header is added to the headers of the response, e.g. Retry-After.
+	Header http.Header
+	// Latency delays the response.
+	Latency time.Duration
+	// Times is the number of requests affected by the fault,
+	// or all the requests if 0.
+	Times int
+
+	hits int
+}
+
+// InjectFault adds a fault to the server. When several faults match a
+// request, the first one injected is used.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all the faults of the server.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns a copy of the first fault matching req and counts the
+// hit, or nil if no fault matches. s.mu must be held.
+func (s *Server) matchFault(req *http.Request) *Fault {
+	for _, f := range s.faults {
+		if f.Method != "" && f.Method != req.Method {
+			continue
+		}
+		if f.Path != "" && f.Path != req.URL.Path {
+			continue
+		}
+		if f.Times > 0 && f.hits >= f.Times {
+			continue
+		}
+		f.hits++
+		fault := *f
+		return &fault
+	}
+	return nil
+}
+
+func (f *Fault) write(w http.ResponseWriter, req *http.Request) {
+	for key, values := range f.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	if f.Body == "" {
+		writeError(w, req, f.StatusCode, "injected fault")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(f.StatusCode)
+	_, _ = w.Write([]byte(f.Body))
+}
@@ -0,0 +1,280 @@
+package okrforjiratest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+// The modes of a Recorder.
+const (
+	// ModeReplay serves the responses of a cassette without network access.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests and records the exchanges in a cassette.
+	ModeRecord
+)
+
+// Redacted replaces the values of the redacted headers in the cassettes.
+const Redacted = "REDACTED"
+
+// ErrInteractionNotFound is returned in replay mode when no interaction of
+// the cassette matches a request.
+var ErrInteractionNotFound = errors.New("okrforjiratest: no matching interaction in cassette")
+
+// Cassette is a list of recorded HTTP exchanges.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded HTTP exchange.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request recorded in a cassette.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// RecordedResponse is a response recorded in a cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Recorder is an http.RoundTripper recording the exchanges with the server in
+// a cassette file, or replaying them from the cassette, e.g. to run
+// integration tests without network access:
+//
+//	rec, err := okrforjiratest.NewRecorder("testdata/export.json", okrforjiratest.ModeReplay, nil)
+//	c := okrforjira.NewClient(nil, token, okrforjira.WithTransport(rec))
+//
+// The API-Token, Authorization and Cookie headers are redacted.
+// In replay mode, the requests are matched on their method, path and query.
+// Identical requests are served the recorded responses in order, the last
+// one being served again once they have all been used.
+// It is safe for concurrent use.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	redact    []string
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder returns a recorder of the cassette at path.
+// In replay mode the cassette is read from path. In record mode the requests
+// are sent with transport, or http.DefaultTransport if nil, and the cassette
+// is written by Save.
+func NewRecorder(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: transport,
+		redact:    []string{"API-Token", "Authorization", "Cookie", "Set-Cookie"},
+	}
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("failed to read the cassette: %w", err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Cassette returns a copy of the interactions recorded or replayed.
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save writes the recorded cassette to its file. It does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "\t")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save the cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to save the cassette: %w", err)
+	}
+	return nil
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	reqBody, req, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readAndRestore(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.RawQuery,
+			Header: r.redactHeader(req.Header),
+			Body:   reqBody,
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.redactHeader(resp.Header),
+			Body:       respBody,
+		},
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !matches(interaction.Request, req) {
+			continue
+		}
+		last = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if last < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, req.Method, req.URL.RequestURI())
+	}
+	r.used[last] = true
+	recorded := r.cassette.Interactions[last].Response
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewBufferString(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// matches reports whether the recorded request has the method, path and
+// query of req. The order of the query parameters is ignored.
+func matches(recorded RecordedRequest, req *http.Request) bool {
+	if recorded.Method != req.Method || recorded.Path != req.URL.Path {
+		return false
+	}
+	recordedQuery, err := url.ParseQuery(recorded.Query)
+	if err != nil {
+		return false
+	}
+	return recordedQuery.Encode() == req.URL.Query().Encode()
+}
+
+func (r *Recorder) redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, key := range r.redact {
+		if _, ok := redacted[http.CanonicalHeaderKey(key)]; ok {
+			redacted.Set(key, Redacted)
+		}
+	}
+	return redacted
+}
+
+// requestBody returns the body of the request and the request to send. As a
+// RoundTripper must not modify the request, the body is read from a copy
+// returned by GetBody or, if there is none, the request is cloned with a
+// reader of the body.
+func requestBody(req *http.Request) (string, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", req, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", nil, err
+		}
+		defer body.Close()
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			return "", nil, err
+		}
+		return string(data), req, nil
+	}
+	data, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return string(data), clone, nil
+}
+
+// readAndRestore reads the body and replaces it with a reader of its content.
+func readAndRestore(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+	data, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return "", err
+	}
+	*body = ioutil.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
@@ -0,0 +1,129 @@
+package okrforjiratest_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grandper/okrforjira"
+	"github.com/grandper/okrforjira/okrforjiratest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	start := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2022, time.March, 31, 0, 0, 0, 0, time.UTC)
+
+	// The exchanges used by both the recording and the replay.
+	run := func(c *okrforjira.Client) ([]okrforjira.Response, okrforjira.Update, error) {
+		byDate, err := c.ObjectivesByDateExpand(ctx, start, deadline, okrforjira.ExpandTeams, okrforjira.ExpandPeriods)
+		if err != nil {
+			return nil, okrforjira.Update{}, err
+		}
+		update, err := c.UpdateKeyResult(ctx, "kr1", okrforjira.StatusAtRisk, 5000, "Slower growth")
+		if err != nil {
+			return nil, okrforjira.Update{}, err
+		}
+		byIDs, err := c.KeyResultsByIDs(ctx, []string{"kr1"}, nil)
+		if err != nil {
+			return nil, okrforjira.Update{}, err
+		}
+		return []okrforjira.Response{byDate, byIDs}, update, nil
+	}
+
+	s := okrforjiratest.NewServer(okrforjiratest.DefaultSeed())
+	rec, err := okrforjiratest.NewRecorder(path, okrforjiratest.ModeRecord, nil)
+	assert.NoError(t, err)
+	recorded, recordedUpdate, err := run(okrforjira.NewClient(nil, s.Token, okrforjira.WithBaseURL(s.URL), okrforjira.WithTransport(rec)))
+	assert.NoError(t, err)
+	assert.NoError(t, rec.Save())
+	s.Close()
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), s.Token)
+	assert.Contains(t, string(data), okrforjiratest.Redacted)
+	assert.Len(t, rec.Cassette().Interactions, 3)
+
+	t.Run("Replay", func(t *testing.T) {
+		rec, err := okrforjiratest.NewRecorder(path, okrforjiratest.ModeReplay, nil)
+		assert.NoError(t, err)
+		c := okrforjira.NewClient(nil, "another token", okrforjira.WithBaseURL(s.URL), okrforjira.WithTransport(rec))
+		replayed, replayedUpdate, err := run(c)
+		assert.NoError(t, err)
+		assert.True(t, cmp.Equal(recorded, replayed), cmp.Diff(recorded, replayed))
+		assert.True(t, cmp.Equal(recordedUpdate, replayedUpdate), cmp.Diff(recordedUpdate, replayedUpdate))
+		assert.Equal(t, 5000.0, replayed[1].KeyResults[0].LatestUpdate.Value.Float64)
+
+		// Identical requests are served the last matching response again.
+		again, err := c.KeyResultsByIDs(ctx, []string{"kr1"}, nil)
+		assert.NoError(t, err)
+		assert.True(t, cmp.Equal(replayed[1], again))
+	})
+
+	t.Run("Unknown request", func(t *testing.T) {
+		rec, err := okrforjiratest.NewRecorder(path, okrforjiratest.ModeReplay, nil)
+		assert.NoError(t, err)
+		c := okrforjira.NewClient(nil, "token", okrforjira.WithBaseURL(s.URL), okrforjira.WithTransport(rec))
+		_, err = c.KeyResultsByIDs(ctx, []string{"kr2"}, nil)
+		assert.True(t, errors.Is(err, okrforjiratest.ErrInteractionNotFound), err)
+		assert.True(t, strings.Contains(err.Error(), "keyResultIds=kr2"), err)
+	})
+
+	t.Run("Missing cassette", func(t *testing.T) {
+		_, err := okrforjiratest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), okrforjiratest.ModeReplay, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestRecorder_RequestBody(t *testing.T) {
+	s := okrforjiratest.NewServer(okrforjiratest.DefaultSeed())
+	defer s.Close()
+	const body = `{"keyResultId":"kr1","status":"AT RISK","newValue":5000,"description":""}`
+
+	newRequest := func(t *testing.T) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, s.URL+okrforjiratest.KeyResultUpdatePath, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("API-Token", s.Token)
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+	record := func(t *testing.T, req *http.Request) {
+		rec, err := okrforjiratest.NewRecorder(filepath.Join(t.TempDir(), "cassette.json"), okrforjiratest.ModeRecord, nil)
+		assert.NoError(t, err)
+		resp, err := rec.RoundTrip(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		if assert.Len(t, rec.Cassette().Interactions, 1) {
+			assert.Equal(t, body, rec.Cassette().Interactions[0].Request.Body)
+		}
+	}
+
+	t.Run("Read from GetBody", func(t *testing.T) {
+		req := newRequest(t)
+		reqBody := req.Body
+		record(t, req)
+		assert.True(t, req.Body == reqBody, "the request body was replaced")
+	})
+
+	t.Run("Without GetBody", func(t *testing.T) {
+		req := newRequest(t)
+		req.GetBody = nil
+		reqBody := req.Body
+		record(t, req)
+		assert.True(t, req.Body == reqBody, "the request body was replaced")
+	})
+}
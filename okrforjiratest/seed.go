@@ -0,0 +1,189 @@
+package okrforjiratest
+
+import (
+	"time"
+
+	"github.com/grandper/okrforjira"
+)
+
+// DefaultSeed returns a small consistent data set: two periods, two teams,
+// two labels, three objectives and four key results.
+//
+//	O-1 Grow the user base (Q1 2022)
+//	├── KR-1 Reach 10000 active users
+//	├── KR-2 Launch the referral program
+//	└── O-3 Improve the onboarding (Q1 2022)
+//	    └── KR-4 Increase the onboarding conversion
+//	O-2 Improve the reliability (Q2 2022)
+//	└── KR-3 Reduce the number of incidents
+//
+// A new data set is returned by every call, so it can be modified freely.
+func DefaultSeed() okrforjira.Response {
+	q1Start := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	q1End := time.Date(2022, time.March, 31, 23, 59, 59, 0, time.UTC)
+	q2Start := time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC)
+	q2End := time.Date(2022, time.June, 30, 23, 59, 59, 0, time.UTC)
+	created := time.Date(2021, time.December, 15, 9, 0, 0, 0, time.UTC)
+
+	return okrforjira.Response{
+		OKRs: []okrforjira.OKR{
+			{
+				ID:                "o1",
+				Key:               "O-1",
+				Name:              "Grow the user base",
+				OwnerAccountID:    "account-1",
+				PercentDone:       95.0 / 3,
+				Created:           created,
+				StartDate:         q1Start,
+				Deadline:          q1End,
+				LabelIDs:          []string{"l-growth"},
+				TeamIDs:           []string{"t-product"},
+				KRIDs:             []string{"kr1", "kr2"},
+				ChildObjectiveIDs: []string{"o3"},
+				LatestUpdate: okrforjira.Update{
+					EntityID: "o1",
+					Status:   okrforjira.StatusOnTrack,
+					Created:  time.Date(2022, time.February, 1, 10, 0, 0, 0, time.UTC),
+				},
+				PeriodAliasID: "p-q1",
+				Weight:        1,
+			},
+			{
+				ID:             "o2",
+				Key:            "O-2",
+				Name:           "Improve the reliability",
+				OwnerAccountID: "account-2",
+				Created:        created,
+				StartDate:      q2Start,
+				Deadline:       q2End,
+				LabelIDs:       []string{"l-quality"},
+				TeamIDs:        []string{"t-ops"},
+				KRIDs:          []string{"kr3"},
+				PeriodAliasID:  "p-q2",
+				Weight:         1,
+			},
+			{
+				ID:                     "o3",
+				Key:                    "O-3",
+				Name:                   "Improve the onboarding",
+				ParentObjectiveID:      "o1",
+				OwnerAccountID:         "account-3",
+				CollaboratorAccountIDs: []string{"account-1"},
+				PercentDone:            20,
+				Created:                created,
+				StartDate:              q1Start,
+				Deadline:               q1End,
+				TeamIDs:                []string{"t-product"},
+				KRIDs:                  []string{"kr4"},
+				PeriodAliasID:          "p-q1",
+				Weight:                 1,
+			},
+		},
+		KeyResults: []okrforjira.KeyResult{
+			{
+				ID:                "kr1",
+				Key:               "KR-1",
+				Name:              "Reach 10000 active users",
+				ParentObjectiveID: "o1",
+				OwnerAccountID:    "account-1",
+				PercentDone:       25,
+				Created:           created,
+				StartDate:         q1Start,
+				Deadline:          q1End,
+				LabelIDs:          []string{"l-growth"},
+				TeamIDs:           []string{"t-product"},
+				PeriodAliasID:     "p-q1",
+				LatestUpdate: okrforjira.Update{
+					EntityID: "kr1",
+					Status:   okrforjira.StatusOnTrack,
+					Created:  time.Date(2022, time.February, 1, 10, 0, 0, 0, time.UTC),
+					Value:    okrforjira.NewNullFloat64(4000),
+				},
+				Unit: okrforjira.Unit{Name: "users"},
+				CurrentProgressDefinition: okrforjira.ProgressDefinition{
+					Type:         okrforjira.ProgressTypeStandard,
+					StartValue:   okrforjira.NewNullFloat64(2000),
+					DesiredValue: okrforjira.NewNullFloat64(10000),
+				},
+				Weight: 1,
+			},
+			{
+				ID:                "kr2",
+				Key:               "KR-2",
+				Name:              "Launch the referral program",
+				ParentObjectiveID: "o1",
+				IssueIDs:          []string{"10001", "10002", "10003", "10004"},
+				OwnerAccountID:    "account-2",
+				PercentDone:       50,
+				Created:           created,
+				StartDate:         q1Start,
+				Deadline:          q1End,
+				TeamIDs:           []string{"t-product"},
+				PeriodAliasID:     "p-q1",
+				CurrentProgressDefinition: okrforjira.ProgressDefinition{
+					Type: okrforjira.ProgressTypeAuto,
+					JQL:  "project = REF",
+				},
+				Weight: 1,
+			},
+			{
+				ID:                "kr3",
+				Key:               "KR-3",
+				Name:              "Reduce the number of incidents",
+				ParentObjectiveID: "o2",
+				OwnerAccountID:    "account-2",
+				Created:           created,
+				StartDate:         q2Start,
+				Deadline:          q2End,
+				LabelIDs:          []string{"l-quality"},
+				TeamIDs:           []string{"t-ops"},
+				PeriodAliasID:     "p-q2",
+				Unit:              okrforjira.Unit{Name: "incidents"},
+				CurrentProgressDefinition: okrforjira.ProgressDefinition{
+					Type:         okrforjira.ProgressTypeStandard,
+					StartValue:   okrforjira.NewNullFloat64(20),
+					DesiredValue: okrforjira.NewNullFloat64(5),
+				},
+				Weight: 1,
+			},
+			{
+				ID:                "kr4",
+				Key:               "KR-4",
+				Name:              "Increase the onboarding conversion",
+				ParentObjectiveID: "o3",
+				OwnerAccountID:    "account-3",
+				PercentDone:       20,
+				Created:           created,
+				StartDate:         q1Start,
+				Deadline:          q1End,
+				TeamIDs:           []string{"t-product"},
+				PeriodAliasID:     "p-q1",
+				LatestUpdate: okrforjira.Update{
+					EntityID: "kr4",
+					Status:   okrforjira.StatusAtRisk,
+					Created:  time.Date(2022, time.February, 15, 10, 0, 0, 0, time.UTC),
+					Value:    okrforjira.NewNullFloat64(33),
+				},
+				Unit: okrforjira.Unit{Name: "percent", Symbol: "%"},
+				CurrentProgressDefinition: okrforjira.ProgressDefinition{
+					Type:         okrforjira.ProgressTypeStandard,
+					StartValue:   okrforjira.NewNullFloat64(30),
+					DesiredValue: okrforjira.NewNullFloat64(45),
+				},
+				Weight: 1,
+			},
+		},
+		Teams: []okrforjira.Team{
+			{ID: "t-product", Name: "Product team"},
+			{ID: "t-ops", Name: "Operations"},
+		},
+		Periods: []okrforjira.Period{
+			{ID: "p-q1", Name: "Q1 2022", StartDate: q1Start, Deadline: q1End},
+			{ID: "p-q2", Name: "Q2 2022", StartDate: q2Start, Deadline: q2End},
+		},
+		Labels: []okrforjira.Label{
+			{ID: "l-growth", Name: "growth"},
+			{ID: "l-quality", Name: "quality"},
+		},
+	}
+}
@@ -0,0 +1,503 @@
+// Package okrforjiratest provides an in-memory fake of the OKR for Jira API
+// to test code using the okrforjira package.
+//
+// The fake server implements the export and update endpoints:
+//
+//	s := okrforjiratest.NewServer(okrforjiratest.DefaultSeed())
+//	defer s.Close()
+//	c := s.Client()
+//	resp, err := c.ObjectivesByIDs(ctx, []string{"o1"}, []string{"TEAMS"})
+package okrforjiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grandper/okrforjira"
+)
+
+// DefaultToken is the API token accepted by a Server unless WithToken is used.
+const DefaultToken = "okrforjiratest-token"
+
+// The endpoints of the OKR for Jira API implemented by the Server.
+const (
+	ObjectivesByDatePath = "/api/v2/api-export/objectives/byDate"
+	ObjectivesByIDsPath  = "/api/v2/api-export/objectives/byIds"
+	KeyResultsByDatePath = "/api/v2/api-export/keyResults/byDate"
+	KeyResultsByIDsPath  = "/api/v2/api-export/keyResults/byIds"
+	ObjectiveUpdatePath  = "/api/v2/api-update/objectives"
+	KeyResultUpdatePath  = "/api/v2/api-update/keyResults"
+)
+
+// Server is a fake OKR for Jira server holding its data in memory.
+// The update endpoints modify the data, which can be read with Response.
+// It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, to be used with okrforjira.WithBaseURL.
+	URL string
+	// Token is the API token accepted by the server.
+	Token string
+
+	server *httptest.Server
+	now    func() time.Time
+
+	mu       sync.Mutex
+	data     okrforjira.Response
+	faults   []*Fault
+	requests []Request
+}
+
+// Option configures a Server created with NewServer.
+type Option func(*Server)
+
+// WithToken sets the API token accepted by the server.
+// The default is DefaultToken.
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.Token = token
+	}
+}
+
+// WithClock sets the function returning the creation date of the updates.
+// The default is time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   string
+}
+
+// NewServer starts a fake server serving the objects of seed.
+// The server must be closed with Close.
+func NewServer(seed okrforjira.Response, opts ...Option) *Server {
+	s := &Server{
+		Token: DefaultToken,
+		now:   time.Now,
+		data:  copyResponse(seed),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(ObjectivesByDatePath, s.handleExport(s.objectivesByDate))
+	mux.HandleFunc(ObjectivesByIDsPath, s.handleExport(s.objectivesByIDs))
+	mux.HandleFunc(KeyResultsByDatePath, s.handleExport(s.keyResultsByDate))
+	mux.HandleFunc(KeyResultsByIDsPath, s.handleExport(s.keyResultsByIDs))
+	mux.HandleFunc(ObjectiveUpdatePath, s.handleUpdate(s.updateObjective))
+	mux.HandleFunc(KeyResultUpdatePath, s.handleUpdate(s.updateKeyResult))
+	s.server = httptest.NewServer(s.middleware(mux))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns a client of the server using its token.
+// The options are applied after the base URL is set.
+func (s *Server) Client(opts ...okrforjira.Option) *okrforjira.Client {
+	opts = append([]okrforjira.Option{okrforjira.WithBaseURL(s.URL)}, opts...)
+	return okrforjira.NewClient(s.server.Client(), s.Token, opts...)
+}
+
+// Response returns a copy of all the objects of the server.
+func (s *Server) Response() okrforjira.Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyResponse(s.data)
+}
+
+// Requests returns the requests received by the server, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := readBody(req)
+		if err != nil {
+			writeError(w, req, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.Body = io.NopCloser(strings.NewReader(body))
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.Query(),
+			Body:   body,
+		})
+		fault := s.matchFault(req)
+		s.mu.Unlock()
+
+		if fault != nil {
+			if fault.Latency > 0 {
+				select {
+				case <-time.After(fault.Latency):
+				case <-req.Context().Done():
+					return
+				}
+			}
+			if fault.StatusCode != 0 {
+				fault.write(w, req)
+				return
+			}
+		}
+		if req.Header.Get("API-Token") != s.Token {
+			writeError(w, req, http.StatusUnauthorized, "invalid API token")
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (s *Server) handleExport(export func(query url.Values, expand []okrforjira.ExpandObject) (okrforjira.Response, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeError(w, req, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		query := req.URL.Query()
+		expand, err := parseExpand(query.Get("expand"))
+		if err != nil {
+			writeError(w, req, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.mu.Lock()
+		response, err := export(query, expand)
+		s.mu.Unlock()
+		if err != nil {
+			writeError(w, req, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func (s *Server) handleUpdate(update func(body []byte) (okrforjira.Update, int, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			writeError(w, req, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		body, _ := readBody(req)
+		s.mu.Lock()
+		result, status, err := update([]byte(body))
+		s.mu.Unlock()
+		if err != nil {
+			writeError(w, req, status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func (s *Server) objectivesByDate(query url.Values, expand []okrforjira.ExpandObject) (okrforjira.Response, error) {
+	start, deadline, err := parseDateRange(query)
+	if err != nil {
+		return okrforjira.Response{}, err
+	}
+	var okrs []okrforjira.OKR
+	for _, o := range s.data.OKRs {
+		if inRange(o.StartDate, start, deadline) || inRange(o.Deadline, start, deadline) {
+			okrs = append(okrs, o)
+		}
+	}
+	return s.expandObjectives(okrs, expand), nil
+}
+
+func (s *Server) objectivesByIDs(query url.Values, expand []okrforjira.ExpandObject) (okrforjira.Response, error) {
+	ids := splitParam(query.Get("objectiveIds"))
+	var okrs []okrforjira.OKR
+	for _, o := range s.data.OKRs {
+		if ids[o.ID] {
+			okrs = append(okrs, o)
+		}
+	}
+	return s.expandObjectives(okrs, expand), nil
+}
+
+func (s *Server) keyResultsByDate(query url.Values, expand []okrforjira.ExpandObject) (okrforjira.Response, error) {
+	start, deadline, err := parseDateRange(query)
+	if err != nil {
+		return okrforjira.Response{}, err
+	}
+	var krs []okrforjira.KeyResult
+	for _, kr := range s.data.KeyResults {
+		if inRange(kr.StartDate, start, deadline) || inRange(kr.Deadline, start, deadline) {
+			krs = append(krs, kr)
+		}
+	}
+	return s.expandKeyResults(krs, expand), nil
+}
+
+func (s *Server) keyResultsByIDs(query url.Values, expand []okrforjira.ExpandObject) (okrforjira.Response, error) {
+	ids := splitParam(query.Get("keyResultIds"))
+	var krs []okrforjira.KeyResult
+	for _, kr := range s.data.KeyResults {
+		if ids[kr.ID] {
+			krs = append(krs, kr)
+		}
+	}
+	return s.expandKeyResults(krs, expand), nil
+}
+
+// expandObjectives returns the response of an objectives export: the key
+// results of the objectives are added when KEY_RESULTS is expanded.
+func (s *Server) expandObjectives(okrs []okrforjira.OKR, expand []okrforjira.ExpandObject) okrforjira.Response {
+	var krs []okrforjira.KeyResult
+	if contains(expand, okrforjira.ExpandKeyResults) {
+		parents := make(map[string]bool, len(okrs))
+		for _, o := range okrs {
+			parents[o.ID] = true
+		}
+		for _, kr := range s.data.KeyResults {
+			if parents[kr.ParentObjectiveID] {
+				krs = append(krs, kr)
+			}
+		}
+	}
+	return s.expandReferences(okrs, krs, expand)
+}
+
+// expandKeyResults returns the response of a key results export: the parent
+// objectives of the key results are added when OBJECTIVES is expanded.
+func (s *Server) expandKeyResults(krs []okrforjira.KeyResult, expand []okrforjira.ExpandObject) okrforjira.Response {
+	var okrs []okrforjira.OKR
+	if contains(expand, okrforjira.ExpandObjectives) {
+		parents := make(map[string]bool, len(krs))
+		for _, kr := range krs {
+			parents[kr.ParentObjectiveID] = true
+		}
+		for _, o := range s.data.OKRs {
+			if parents[o.ID] {
+				okrs = append(okrs, o)
+			}
+		}
+	}
+	return s.expandReferences(okrs, krs, expand)
+}
+
+// expandReferences adds the expanded teams, periods and labels referenced by
+// the objectives and key results. The lists that are not expanded are empty.
+func (s *Server) expandReferences(okrs []okrforjira.OKR, krs []okrforjira.KeyResult, expand []okrforjira.ExpandObject) okrforjira.Response {
+	teams := make(map[string]bool)
+	periods := make(map[string]bool)
+	labels := make(map[string]bool)
+	for _, o := range okrs {
+		addAll(teams, o.TeamIDs)
+		addAll(labels, o.LabelIDs)
+		periods[o.PeriodAliasID] = true
+	}
+	for _, kr := range krs {
+		addAll(teams, kr.TeamIDs)
+		addAll(labels, kr.LabelIDs)
+		periods[kr.PeriodAliasID] = true
+	}
+
+	response := okrforjira.Response{
+		OKRs:       append([]okrforjira.OKR{}, okrs...),
+		KeyResults: append([]okrforjira.KeyResult{}, krs...),
+		Teams:      []okrforjira.Team{},
+		Periods:    []okrforjira.Period{},
+		Labels:     []okrforjira.Label{},
+	}
+	if contains(expand, okrforjira.ExpandTeams) {
+		response.Teams = filter(s.data.Teams, func(t okrforjira.Team) bool { return teams[t.ID] })
+	}
+	if contains(expand, okrforjira.ExpandPeriods) {
+		response.Periods = filter(s.data.Periods, func(p okrforjira.Period) bool { return periods[p.ID] })
+	}
+	if contains(expand, okrforjira.ExpandLabels) {
+		response.Labels = filter(s.data.Labels, func(l okrforjira.Label) bool { return labels[l.ID] })
+	}
+	return response
+}
+
+type objectiveUpdateRequest struct {
+	ObjectiveID string `json:"objectiveId"`
+	Status      string `json:"status"`
+	Description string `json:"description"`
+}
+
+type keyResultUpdateRequest struct {
+	KeyResultID string   `json:"keyResultId"`
+	Status      string   `json:"status"`
+	NewValue    *float64 `json:"newValue"`
+	Description string   `json:"description"`
+}
+
+func (s *Server) updateObjective(body []byte) (okrforjira.Update, int, error) {
+	var request objectiveUpdateRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return okrforjira.Update{}, http.StatusBadRequest, err
+	}
+	status, err := okrforjira.ParseStatus(request.Status)
+	if err != nil {
+		return okrforjira.Update{}, http.StatusBadRequest, err
+	}
+	for i := range s.data.OKRs {
+		o := &s.data.OKRs[i]
+		if o.ID != request.ObjectiveID {
+			continue
+		}
+		o.LatestUpdate = okrforjira.Update{
+			EntityID:    o.ID,
+			Status:      status,
+			Created:     s.now(),
+			Description: request.Description,
+		}
+		return o.LatestUpdate, http.StatusOK, nil
+	}
+	return okrforjira.Update{}, http.StatusNotFound, fmt.Errorf("objective %q not found", request.ObjectiveID)
+}
+
+func (s *Server) updateKeyResult(body []byte) (okrforjira.Update, int, error) {
+	var request keyResultUpdateRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return okrforjira.Update{}, http.StatusBadRequest, err
+	}
+	status, err := okrforjira.ParseStatus(request.Status)
+	if err != nil {
+		return okrforjira.Update{}, http.StatusBadRequest, err
+	}
+	if request.NewValue == nil {
+		return okrforjira.Update{}, http.StatusBadRequest, fmt.Errorf("missing newValue")
+	}
+	for i := range s.data.KeyResults {
+		kr := &s.data.KeyResults[i]
+		if kr.ID != request.KeyResultID {
+			continue
+		}
+		kr.LatestUpdate = okrforjira.Update{
+			EntityID:    kr.ID,
+			Status:      status,
+			Created:     s.now(),
+			Value:       okrforjira.NewNullFloat64(*request.NewValue),
+			Description: request.Description,
+		}
+		if percent, err := kr.Percent(*request.NewValue); err == nil {
+			kr.PercentDone = percent
+		}
+		return kr.LatestUpdate, http.StatusOK, nil
+	}
+	return okrforjira.Update{}, http.StatusNotFound, fmt.Errorf("key result %q not found", request.KeyResultID)
+}
+
+func parseExpand(param string) ([]okrforjira.ExpandObject, error) {
+	var expand []okrforjira.ExpandObject
+	for _, object := range strings.Split(param, ",") {
+		if object == "" {
+			continue
+		}
+		o := okrforjira.ExpandObject(object)
+		if err := o.Validate(); err != nil {
+			return nil, err
+		}
+		expand = append(expand, o)
+	}
+	return expand, nil
+}
+
+func parseDateRange(query url.Values) (time.Time, time.Time, error) {
+	start, err := strconv.ParseInt(query.Get("startDateEpochMilli"), 10, 64)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid startDateEpochMilli: %w", err)
+	}
+	deadline, err := strconv.ParseInt(query.Get("deadlineEpochMilli"), 10, 64)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid deadlineEpochMilli: %w", err)
+	}
+	return time.UnixMilli(start), time.UnixMilli(deadline), nil
+}
+
+func inRange(t, start, deadline time.Time) bool {
+	return !t.IsZero() && !t.Before(start) && !t.After(deadline)
+}
+
+func splitParam(param string) map[string]bool {
+	values := make(map[string]bool)
+	addAll(values, strings.Split(param, ","))
+	return values
+}
+
+func addAll(set map[string]bool, values []string) {
+	for _, v := range values {
+		if v != "" {
+			set[v] = true
+		}
+	}
+}
+
+func contains(expand []okrforjira.ExpandObject, object okrforjira.ExpandObject) bool {
+	for _, o := range expand {
+		if o == object {
+			return true
+		}
+	}
+	return false
+}
+
+func filter[T any](items []T, keep func(T) bool) []T {
+	result := []T{}
+	for _, item := range items {
+		if keep(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func copyResponse(r okrforjira.Response) okrforjira.Response {
+	return okrforjira.Response{
+		OKRs:       append([]okrforjira.OKR(nil), r.OKRs...),
+		KeyResults: append([]okrforjira.KeyResult(nil), r.KeyResults...),
+		Teams:      append([]okrforjira.Team(nil), r.Teams...),
+		Periods:    append([]okrforjira.Period(nil), r.Periods...),
+		Labels:     append([]okrforjira.Label(nil), r.Labels...),
+	}
+}
+
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	var sb strings.Builder
+	_, err := io.Copy(&sb, req.Body)
+	return sb.String(), err
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error body like the ones of the OKR for Jira API.
+func writeError(w http.ResponseWriter, req *http.Request, status int, message string) {
+	writeJSON(w, status, okrforjira.ErrorBody{
+		Status:  status,
+		Error:   http.StatusText(status),
+		Message: message,
+		Path:    req.URL.Path,
+	})
+}
@@ -0,0 +1,194 @@
+package okrforjiratest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grandper/okrforjira"
+	"github.com/grandper/okrforjira/okrforjiratest"
+	"github.com/stretchr/testify/assert"
+)
+
+func keys(r okrforjira.Response) (okrs, krs []string) {
+	for _, o := range r.OKRs {
+		okrs = append(okrs, o.Key)
+	}
+	for _, kr := range r.KeyResults {
+		krs = append(krs, kr.Key)
+	}
+	return okrs, krs
+}
+
+func TestDefaultSeed(t *testing.T) {
+	seed := okrforjiratest.DefaultSeed()
+	assert.Empty(t, okrforjira.Validate(seed))
+	assert.Empty(t, okrforjira.NewProgressEngine(seed).Discrepancies(0.01))
+}
+
+func TestServer_Export(t *testing.T) {
+	s := okrforjiratest.NewServer(okrforjiratest.DefaultSeed())
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	q1Start := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	q1End := time.Date(2022, time.March, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("ObjectivesByDate", func(t *testing.T) {
+		got, err := c.ObjectivesByDateExpand(ctx, q1Start, q1End)
+		assert.NoError(t, err)
+		okrs, krs := keys(got)
+		assert.Equal(t, []string{"O-1", "O-3"}, okrs)
+		assert.Empty(t, krs)
+		assert.Empty(t, got.Teams)
+	})
+
+	t.Run("ObjectivesByIDs with expand", func(t *testing.T) {
+		got, err := c.ObjectivesByIDsExpand(ctx, []string{"o2", "unknown"}, okrforjira.ExpandAll()...)
+		assert.NoError(t, err)
+		okrs, krs := keys(got)
+		assert.Equal(t, []string{"O-2"}, okrs)
+		assert.Equal(t, []string{"KR-3"}, krs)
+		assert.Equal(t, []okrforjira.Team{{ID: "t-ops", Name: "Operations"}}, got.Teams)
+		assert.Equal(t, []okrforjira.Label{{ID: "l-quality", Name: "quality"}}, got.Labels)
+		assert.Len(t, got.Periods, 1)
+		assert.Equal(t, "Q2 2022", got.Periods[0].Name)
+	})
+
+	t.Run("KeyResultsByDate with expand", func(t *testing.T) {
+		got, err := c.KeyResultsByDateExpand(ctx, q1Start, q1End, okrforjira.ExpandObjectives, okrforjira.ExpandTeams)
+		assert.NoError(t, err)
+		okrs, krs := keys(got)
+		assert.Equal(t, []string{"O-1", "O-3"}, okrs)
+		assert.Equal(t, []string{"KR-1", "KR-2", "KR-4"}, krs)
+		assert.Equal(t, []okrforjira.Team{{ID: "t-product", Name: "Product team"}}, got.Teams)
+		assert.Empty(t, got.Periods)
+	})
+
+	t.Run("KeyResultsByIDs", func(t *testing.T) {
+		got, err := c.KeyResultsByIDs(ctx, []string{"kr4", "kr1"}, nil)
+		assert.NoError(t, err)
+		okrs, krs := keys(got)
+		assert.Empty(t, okrs)
+		assert.Equal(t, []string{"KR-1", "KR-4"}, krs)
+		want := okrforjiratest.DefaultSeed().KeyResults[0]
+		assert.True(t, cmp.Equal(want, got.KeyResults[0]), cmp.Diff(want, got.KeyResults[0]))
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		c := okrforjira.NewClient(nil, "wrong", okrforjira.WithBaseURL(s.URL))
+		_, err := c.KeyResultsByIDs(ctx, []string{"kr1"}, nil)
+		assert.ErrorIs(t, err, okrforjira.ErrUnauthorized)
+	})
+
+	requests := s.Requests()
+	assert.NotEmpty(t, requests)
+	assert.Equal(t, http.MethodGet, requests[0].Method)
+	assert.Equal(t, okrforjiratest.ObjectivesByDatePath, requests[0].Path)
+}
+
+func TestServer_Update(t *testing.T) {
+	now := time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+	s := okrforjiratest.NewServer(okrforjiratest.DefaultSeed(), okrforjiratest.WithClock(func() time.Time { return now }))
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	t.Run("Objective", func(t *testing.T) {
+		got, err := c.UpdateObjective(ctx, "o2", okrforjira.StatusDelayed, "Waiting for the new cluster")
+		assert.NoError(t, err)
+		want := okrforjira.Update{
+			EntityID:    "o2",
+			Status:      okrforjira.StatusDelayed,
+			Created:     now,
+			Description: "Waiting for the new cluster",
+		}
+		assert.True(t, want.Created.Equal(got.Created))
+		got.Created = want.Created
+		assert.Equal(t, want, got)
+
+		r, err := c.ObjectivesByIDs(ctx, []string{"o2"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, okrforjira.StatusDelayed, r.OKRs[0].LatestUpdate.Status)
+	})
+
+	t.Run("Key result", func(t *testing.T) {
+		got, err := c.UpdateKeyResult(ctx, "kr3", okrforjira.StatusOnTrack, 11, "Fewer incidents")
+		assert.NoError(t, err)
+		assert.Equal(t, okrforjira.NewNullFloat64(11), got.Value)
+
+		kr := s.Response().KeyResults[2]
+		assert.Equal(t, "kr3", kr.ID)
+		assert.Equal(t, okrforjira.NewNullFloat64(11), kr.LatestUpdate.Value)
+		assert.InDelta(t, 60, kr.PercentDone, 1e-9)
+	})
+
+	t.Run("Unknown entity", func(t *testing.T) {
+		_, err := c.UpdateKeyResult(ctx, "unknown", okrforjira.StatusOnTrack, 1, "")
+		assert.ErrorIs(t, err, okrforjira.ErrNotFound)
+		var apiErr *okrforjira.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, `key result "unknown" not found`, apiErr.ErrorBody.Message)
+	})
+
+	t.Run("Seed is not modified", func(t *testing.T) {
+		seed := okrforjiratest.DefaultSeed()
+		s := okrforjiratest.NewServer(seed)
+		defer s.Close()
+		_, err := s.Client().UpdateObjective(ctx, "o1", okrforjira.StatusAtRisk, "")
+		assert.NoError(t, err)
+		assert.Equal(t, okrforjiratest.DefaultSeed(), seed)
+	})
+}
+
+func TestServer_Faults(t *testing.T) {
+	s := okrforjiratest.NewServer(okrforjiratest.DefaultSeed())
+	defer s.Close()
+	ctx := context.Background()
+
+	t.Run("Status code", func(t *testing.T) {
+		s.InjectFault(okrforjiratest.Fault{
+			Path:       okrforjiratest.ObjectivesByIDsPath,
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"0"}},
+			Times:      2,
+		})
+		defer s.ClearFaults()
+
+		_, err := s.Client().ObjectivesByIDs(ctx, []string{"o1"}, nil)
+		assert.ErrorIs(t, err, okrforjira.ErrRateLimited)
+
+		policy := okrforjira.DefaultRetryPolicy()
+		policy.BaseBackoff = time.Millisecond
+		c := s.Client(okrforjira.WithRetryPolicy(policy))
+		got, err := c.ObjectivesByIDs(ctx, []string{"o1"}, nil)
+		assert.NoError(t, err)
+		assert.Len(t, got.OKRs, 1)
+
+		// The other endpoints are not affected.
+		_, err = s.Client().KeyResultsByIDs(ctx, []string{"kr1"}, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		s.InjectFault(okrforjiratest.Fault{Method: http.MethodGet, Latency: time.Second})
+		defer s.ClearFaults()
+
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err := s.Client().ObjectivesByIDs(ctx, []string{"o1"}, nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Custom body", func(t *testing.T) {
+		s.InjectFault(okrforjiratest.Fault{StatusCode: http.StatusOK, Body: `{"okrs": [{"id": "fake"}]}`, Times: 1})
+		defer s.ClearFaults()
+
+		got, err := s.Client().ObjectivesByIDs(ctx, []string{"o1"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []okrforjira.OKR{{ID: "fake"}}, got.OKRs)
+	})
+}
@@ -0,0 +1,75 @@
+package okrforjira
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a Client created with NewClient.
+type Option func(*config)
+
+type config struct {
+	baseURL      string
+	userAgent    string
+	extraHeaders http.Header
+	timeout      time.Duration
+	timeoutSet   bool
+	transport    http.RoundTripper
+	retryPolicy  *RetryPolicy
+	rateLimiter  *RateLimiter
+
+	idBatchSize      int
+	batchConcurrency int
+	dateSplitter     DateSplitter
+
+	location       *time.Location
+	driftReport    *DriftReport
+	strictDecoding bool
+}
+
+// WithBaseURL sets the base URL of the OKR for Jira API, e.g. to target a
+// staging instance, a recording proxy or a local fake server.
+// The default is https://okr-for-jira-prod.herokuapp.com.
+func WithBaseURL(baseURL string) Option {
+	return func(c *config) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *config) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithExtraHeaders adds headers to every request.
+// The API-Token, Content-Type and Accept headers cannot be overridden.
+func WithExtraHeaders(headers http.Header) Option {
+	return func(c *config) {
+		if c.extraHeaders == nil {
+			c.extraHeaders = make(http.Header)
+		}
+		for key, values := range headers {
+			for _, value := range values {
+				c.extraHeaders.Add(key, value)
+			}
+		}
+	}
+}
+
+// WithTimeout sets the timeout of the underlying http.Client.
+// The default is 10 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+		c.timeoutSet = true
+	}
+}
+
+// WithTransport sets the transport of the underlying http.Client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *config) {
+		c.transport = transport
+	}
+}
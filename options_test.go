@@ -0,0 +1,84 @@
+package okrforjira_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient_Options(t *testing.T) {
+	okResponse := func() *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+			Header:     make(http.Header),
+		}
+	}
+	ctx := context.Background()
+
+	t.Run("WithBaseURL", func(t *testing.T) {
+		var urls []string
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			urls = append(urls, req.URL.String())
+			return okResponse()
+		})
+		c := okrforjira.NewClient(client, token, okrforjira.WithBaseURL("http://localhost:8080/"))
+		_, err := c.ObjectivesByIDs(ctx, []string{"1"}, nil)
+		assert.NoError(t, err)
+		_, err = c.UpdateObjective(ctx, "1", "ON_TRACK", "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"http://localhost:8080/api/v2/api-export/objectives/byIds?objectiveIds=1&expand=",
+			"http://localhost:8080/api/v2/api-update/objectives",
+		}, urls)
+	})
+
+	t.Run("WithUserAgent and WithExtraHeaders", func(t *testing.T) {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			assert.Equal(t, "okr-exporter/1.0", req.Header.Get("User-Agent"))
+			assert.Equal(t, "abc", req.Header.Get("X-Request-Id"))
+			assert.Equal(t, token, req.Header.Get("API-Token"))
+			return okResponse()
+		})
+		c := okrforjira.NewClient(client, token,
+			okrforjira.WithUserAgent("okr-exporter/1.0"),
+			okrforjira.WithExtraHeaders(http.Header{
+				"X-Request-Id": []string{"abc"},
+				"Api-Token":    []string{"overridden"},
+			}),
+		)
+		_, err := c.KeyResultsByIDs(ctx, []string{"1"}, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("WithTransport and WithTimeout", func(t *testing.T) {
+		called := false
+		transport := RoundTripFunc(func(req *http.Request) *http.Response {
+			called = true
+			<-req.Context().Done()
+			return nil
+		})
+		httpClient := &http.Client{}
+		c := okrforjira.NewClient(httpClient, token,
+			okrforjira.WithTransport(transport),
+			okrforjira.WithTimeout(10*time.Millisecond),
+		)
+		_, err := c.ObjectivesByIDs(ctx, []string{"1"}, nil)
+		assert.Error(t, err)
+		assert.True(t, called)
+		// The http.Client provided by the caller is left untouched.
+		assert.Nil(t, httpClient.Transport)
+		assert.Zero(t, httpClient.Timeout)
+	})
+
+	t.Run("Options without http.Client", func(t *testing.T) {
+		c := okrforjira.NewClient(nil, token, okrforjira.WithTimeout(time.Second))
+		assert.NotNil(t, c)
+	})
+}
@@ -0,0 +1,168 @@
+package okrforjira
+
+import (
+	"fmt"
+	"math"
+)
+
+// ProgressEngine recomputes the progress of the objectives of a Response from
+// the progress of their key results and child objectives, weighted by their Weight.
+// It can simulate the progress of key results, e.g. "what if KR-8 reaches 80%",
+// and report the discrepancies with the progress reported by the server.
+type ProgressEngine struct {
+	r         Response
+	idx       *Index
+	overrides map[string]float64
+}
+
+// ProgressDiscrepancy is an objective or key result whose recomputed progress
+// differs from the PercentDone reported by the server.
+type ProgressDiscrepancy struct {
+	EntityID  string
+	EntityKey string
+	Reported  float64
+	Computed  float64
+}
+
+// NewProgressEngine creates a progress engine for the response.
+func NewProgressEngine(r Response) *ProgressEngine {
+	return &ProgressEngine{
+		r:         r,
+		idx:       NewIndex(r),
+		overrides: make(map[string]float64),
+	}
+}
+
+// SetKeyResultPercent simulates the progress, in percent, of the key result with
+// the given ID or key.
+func (e *ProgressEngine) SetKeyResultPercent(ref string, percent float64) error {
+	kr, err := e.keyResult(ref)
+	if err != nil {
+		return err
+	}
+	e.overrides[kr.ID] = percent
+	return nil
+}
+
+// SetKeyResultValue simulates the current value of the key result with the given
+// ID or key, see KeyResult.Percent.
+func (e *ProgressEngine) SetKeyResultValue(ref string, value float64) error {
+	kr, err := e.keyResult(ref)
+	if err != nil {
+		return err
+	}
+	percent, err := kr.Percent(value)
+	if err != nil {
+		return fmt.Errorf("key result %s: %w", ref, err)
+	}
+	e.overrides[kr.ID] = percent
+	return nil
+}
+
+// Reset removes all the simulated progress.
+func (e *ProgressEngine) Reset() {
+	e.overrides = make(map[string]float64)
+}
+
+// KeyResultPercent returns the progress, in percent, of the key result with the given ID or key.
+// The progress is computed from the value of its latest update, see KeyResult.Percent.
+// The progress reported by the server is used when it cannot be computed.
+func (e *ProgressEngine) KeyResultPercent(ref string) (float64, error) {
+	kr, err := e.keyResult(ref)
+	if err != nil {
+		return 0, err
+	}
+	return e.keyResultPercent(kr), nil
+}
+
+// ObjectivePercent returns the progress, in percent, of the objective with the given ID or key,
+// computed as the weighted average of the progress of its key results and child objectives.
+// The key results and child objectives that are not part of the response are ignored;
+// the progress reported by the server is returned when none is left, e.g. when the key
+// results were not expanded, or when all their weights are 0.
+func (e *ProgressEngine) ObjectivePercent(ref string) (float64, error) {
+	okr, ok := e.idx.OKR(ref)
+	if !ok {
+		if okr, ok = e.idx.OKRByKey(ref); !ok {
+			return 0, fmt.Errorf("unknown objective %s", ref)
+		}
+	}
+	return e.objectivePercent(okr, make(map[string]bool)), nil
+}
+
+// Discrepancies returns the objectives and key results whose recomputed
+// progress differs by more than tolerance from the progress reported by the server.
+func (e *ProgressEngine) Discrepancies(tolerance float64) []ProgressDiscrepancy {
+	var discrepancies []ProgressDiscrepancy
+	for i := range e.r.OKRs {
+		okr := &e.r.OKRs[i]
+		computed := e.objectivePercent(okr, make(map[string]bool))
+		if math.Abs(computed-okr.PercentDone) > tolerance {
+			discrepancies = append(discrepancies, ProgressDiscrepancy{
+				EntityID:  okr.ID,
+				EntityKey: okr.Key,
+				Reported:  okr.PercentDone,
+				Computed:  computed,
+			})
+		}
+	}
+	for i := range e.r.KeyResults {
+		kr := &e.r.KeyResults[i]
+		computed := e.keyResultPercent(kr)
+		if math.Abs(computed-kr.PercentDone) > tolerance {
+			discrepancies = append(discrepancies, ProgressDiscrepancy{
+				EntityID:  kr.ID,
+				EntityKey: kr.Key,
+				Reported:  kr.PercentDone,
+				Computed:  computed,
+			})
+		}
+	}
+	return discrepancies
+}
+
+func (e *ProgressEngine) keyResult(ref string) (*KeyResult, error) {
+	if kr, ok := e.idx.KeyResult(ref); ok {
+		return kr, nil
+	}
+	if kr, ok := e.idx.KeyResultByKey(ref); ok {
+		return kr, nil
+	}
+	return nil, fmt.Errorf("unknown key result %s", ref)
+}
+
+func (e *ProgressEngine) keyResultPercent(kr *KeyResult) float64 {
+	if percent, ok := e.overrides[kr.ID]; ok {
+		return percent
+	}
+	if value, ok := kr.LatestUpdate.Value.Get(); ok {
+		if percent, err := kr.Percent(value); err == nil {
+			return percent
+		}
+	}
+	return kr.PercentDone
+}
+
+// objectivePercent computes the progress of the objective.
+// visiting holds the objectives being computed to break cycles.
+func (e *ProgressEngine) objectivePercent(okr *OKR, visiting map[string]bool) float64 {
+	visiting[okr.ID] = true
+	defer delete(visiting, okr.ID)
+
+	var sum, totalWeight float64
+	for _, kr := range e.idx.KeyResultsOf(*okr) {
+		sum += kr.Weight * e.keyResultPercent(kr)
+		totalWeight += kr.Weight
+	}
+	for _, child := range e.idx.ChildrenOf(*okr) {
+		if visiting[child.ID] {
+			continue
+		}
+		sum += child.Weight * e.objectivePercent(child, visiting)
+		totalWeight += child.Weight
+	}
+	if totalWeight == 0 {
+		return okr.PercentDone
+	}
+	return sum / totalWeight
+}
@@ -0,0 +1,119 @@
+package okrforjira_test
+
+import (
+	"testing"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+// newProgressResponse returns the objectives and key results of the
+// KeyResultsByDate example, with their progress as reported by the server.
+func newProgressResponse() okrforjira.Response {
+	standard := okrforjira.ProgressDefinition{
+		Type:         okrforjira.ProgressTypeStandard,
+		StartValue:   okrforjira.NewNullFloat64(0),
+		DesiredValue: okrforjira.NewNullFloat64(1),
+	}
+	return okrforjira.Response{
+		OKRs: []okrforjira.OKR{
+			{ID: "620265fe81300a4c89e89806", Key: "O-99", PercentDone: 4.761904761904762, Weight: 1,
+				KRIDs:             []string{"62027c6f81300a4c89e89a1c"},
+				ChildObjectiveIDs: []string{"6202664f81300a4c89e89810", "6202666281300a4c89e89812"}},
+			{ID: "6202664f81300a4c89e89810", Key: "O-101", PercentDone: 0, Weight: 1,
+				ParentObjectiveID: "620265fe81300a4c89e89806",
+				KRIDs:             []string{"6202671581300a4c89e89827", "6202672781300a4c89e8982a"}},
+			{ID: "6202666281300a4c89e89812", Key: "O-102", PercentDone: 9.523809523809524, Weight: 1,
+				ParentObjectiveID: "620265fe81300a4c89e89806",
+				KRIDs:             []string{"6202673e81300a4c89e8982e", "6202674e81300a4c89e89830", "62029d8881300a4c89e8af27"}},
+		},
+		KeyResults: []okrforjira.KeyResult{
+			{ID: "6202671581300a4c89e89827", Key: "KR-96", ParentObjectiveID: "6202664f81300a4c89e89810", Weight: 1, CurrentProgressDefinition: standard},
+			{ID: "6202672781300a4c89e8982a", Key: "KR-97", ParentObjectiveID: "6202664f81300a4c89e89810", Weight: 1, CurrentProgressDefinition: standard},
+			{ID: "6202673e81300a4c89e8982e", Key: "KR-98", ParentObjectiveID: "6202666281300a4c89e89812", Weight: 1, CurrentProgressDefinition: standard},
+			{ID: "6202674e81300a4c89e89830", Key: "KR-99", ParentObjectiveID: "6202666281300a4c89e89812", Weight: 1, CurrentProgressDefinition: standard},
+			{ID: "62027c6f81300a4c89e89a1c", Key: "KR-103", ParentObjectiveID: "620265fe81300a4c89e89806", Weight: 0, CurrentProgressDefinition: standard},
+			{ID: "62029d8881300a4c89e8af27", Key: "KR-104", ParentObjectiveID: "6202666281300a4c89e89812", Weight: 1, PercentDone: 28.57142857142857,
+				IssueIDs:                  []string{"10008", "10007", "10009", "10000", "10002", "10001", "10004"},
+				LatestUpdate:              okrforjira.Update{Value: okrforjira.NewNullFloat64(2)},
+				CurrentProgressDefinition: okrforjira.ProgressDefinition{Type: okrforjira.ProgressTypeAuto, JQL: "project = ddd"}},
+		},
+	}
+}
+
+func TestProgressEngine(t *testing.T) {
+	t.Run("The recomputed progress matches the server", func(t *testing.T) {
+		e := okrforjira.NewProgressEngine(newProgressResponse())
+		assert.Empty(t, e.Discrepancies(1e-9))
+
+		percent, err := e.ObjectivePercent("O-99")
+		assert.NoError(t, err)
+		assert.InDelta(t, 4.761904761904762, percent, 1e-9)
+	})
+
+	t.Run("Key results not expanded", func(t *testing.T) {
+		r := newProgressResponse()
+		r.KeyResults = nil
+		e := okrforjira.NewProgressEngine(r)
+		assert.Empty(t, e.Discrepancies(1e-9))
+
+		// O-99 falls back on the progress of its children, which fall back
+		// on the progress reported by the server.
+		percent, err := e.ObjectivePercent("O-99")
+		assert.NoError(t, err)
+		assert.InDelta(t, (0+9.523809523809524)/2, percent, 1e-9)
+		percent, err = e.ObjectivePercent("O-102")
+		assert.NoError(t, err)
+		assert.InDelta(t, 9.523809523809524, percent, 1e-9)
+	})
+
+	t.Run("What if", func(t *testing.T) {
+		e := okrforjira.NewProgressEngine(newProgressResponse())
+		assert.NoError(t, e.SetKeyResultValue("KR-104", 7))
+		assert.NoError(t, e.SetKeyResultValue("6202673e81300a4c89e8982e", 0.5))
+
+		percent, err := e.KeyResultPercent("KR-98")
+		assert.NoError(t, err)
+		assert.Equal(t, 50.0, percent)
+		percent, err = e.ObjectivePercent("O-102")
+		assert.NoError(t, err)
+		assert.InDelta(t, 50.0, percent, 1e-9)
+		// KR-103 has no weight, so O-99 is the average of O-101 and O-102.
+		percent, err = e.ObjectivePercent("620265fe81300a4c89e89806")
+		assert.NoError(t, err)
+		assert.InDelta(t, 25.0, percent, 1e-9)
+
+		assert.Equal(t, []okrforjira.ProgressDiscrepancy{
+			{EntityID: "620265fe81300a4c89e89806", EntityKey: "O-99", Reported: 4.761904761904762, Computed: 25},
+			{EntityID: "6202666281300a4c89e89812", EntityKey: "O-102", Reported: 9.523809523809524, Computed: 50},
+			{EntityID: "6202673e81300a4c89e8982e", EntityKey: "KR-98", Reported: 0, Computed: 50},
+			{EntityID: "62029d8881300a4c89e8af27", EntityKey: "KR-104", Reported: 28.57142857142857, Computed: 100},
+		}, e.Discrepancies(1e-9))
+
+		e.Reset()
+		assert.Empty(t, e.Discrepancies(1e-9))
+	})
+
+	t.Run("STANDARD values are clamped", func(t *testing.T) {
+		e := okrforjira.NewProgressEngine(newProgressResponse())
+		assert.NoError(t, e.SetKeyResultValue("KR-96", 3))
+		percent, _ := e.KeyResultPercent("KR-96")
+		assert.Equal(t, 100.0, percent)
+		assert.NoError(t, e.SetKeyResultValue("KR-96", -1))
+		percent, _ = e.KeyResultPercent("KR-96")
+		assert.Equal(t, 0.0, percent)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		e := okrforjira.NewProgressEngine(newProgressResponse())
+		r := newProgressResponse()
+		r.KeyResults[5].IssueIDs = nil
+		e = okrforjira.NewProgressEngine(r)
+		err := e.SetKeyResultValue("KR-104", 3)
+		assert.ErrorIs(t, err, okrforjira.ErrNoProgress)
+		assert.EqualError(t, err, "key result KR-104: progress cannot be computed for an AUTO key result without issues")
+		assert.EqualError(t, e.SetKeyResultPercent("KR-1", 3), "unknown key result KR-1")
+		_, err = e.ObjectivePercent("O-1")
+		assert.EqualError(t, err, "unknown objective O-1")
+	})
+}
@@ -0,0 +1,159 @@
+package okrforjira
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the rate of the requests sent to the API.
+// It is safe for concurrent use and can be shared by several clients with WithRateLimiter.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+	stats  RateLimiterStats
+}
+
+// RateLimiterStats are statistics about the requests that went through a RateLimiter.
+type RateLimiterStats struct {
+	// Requests is the number of requests allowed so far.
+	Requests int64
+	// Delayed is the number of requests that had to wait.
+	Delayed int64
+	// Waiting is the number of requests currently waiting.
+	Waiting int
+	// TotalWait is the cumulated waiting time of all the requests.
+	TotalWait time.Duration
+	// MaxWait is the longest waiting time of a request.
+	MaxWait time.Duration
+}
+
+// NewRateLimiter creates a rate limiter allowing requestsPerSecond requests per second
+// on average, with bursts of up to burst requests.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// WithRateLimit limits the client to requestsPerSecond requests per second
+// on average, with bursts of up to burst requests.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return WithRateLimiter(NewRateLimiter(requestsPerSecond, burst))
+}
+
+// WithRateLimiter limits the client with the provided rate limiter.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *config) {
+		c.rateLimiter = limiter
+	}
+}
+
+// Wait blocks until a request is allowed or ctx is done.
+// It returns an error without waiting if ctx would expire before the request is allowed.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay, err := l.reserve(ctx)
+	if err != nil {
+		return err
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		l.done(delay)
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stats returns the statistics of the rate limiter.
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// reserve takes a token and returns the delay before it is available.
+func (l *RateLimiter) reserve(ctx context.Context) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.advance(now)
+	var delay time.Duration
+	if l.tokens < 1 {
+		if l.rate <= 0 {
+			return 0, fmt.Errorf("rate limiter does not allow any request")
+		}
+		delay = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(now) < delay {
+		return 0, fmt.Errorf("rate limiter wait of %s exceeds the context deadline: %w", delay, context.DeadlineExceeded)
+	}
+
+	l.tokens--
+	l.stats.Requests++
+	if delay > 0 {
+		l.stats.Waiting++
+	}
+	return delay, nil
+}
+
+// done records the wait of a request that was allowed after delay. The waits
+// are only recorded once complete, so that cancelled waits are not counted.
+func (l *RateLimiter) done(delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stats.Waiting--
+	l.stats.Delayed++
+	l.stats.TotalWait += delay
+	if delay > l.stats.MaxWait {
+		l.stats.MaxWait = delay
+	}
+}
+
+// cancel gives back the token of a request that stopped waiting.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(time.Now())
+	l.tokens++
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+	l.stats.Requests--
+	l.stats.Waiting--
+}
+
+// advance refills the bucket with the tokens accumulated since the last call.
+func (l *RateLimiter) advance(now time.Time) {
+	elapsed := now.Sub(l.last)
+	if elapsed <= 0 {
+		return
+	}
+	l.last = now
+	l.tokens += elapsed.Seconds() * l.rate
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+}
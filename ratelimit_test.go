@@ -0,0 +1,94 @@
+package okrforjira_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("Bursts are not delayed", func(t *testing.T) {
+		l := okrforjira.NewRateLimiter(1, 5)
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, l.Wait(context.Background()))
+		}
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+		stats := l.Stats()
+		assert.Equal(t, int64(5), stats.Requests)
+		assert.Equal(t, int64(0), stats.Delayed)
+	})
+
+	t.Run("Concurrent requests are spread over time", func(t *testing.T) {
+		l := okrforjira.NewRateLimiter(100, 1)
+		start := time.Now()
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, l.Wait(context.Background()))
+			}()
+		}
+		wg.Wait()
+		assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+		stats := l.Stats()
+		assert.Equal(t, int64(10), stats.Requests)
+		assert.Equal(t, int64(9), stats.Delayed)
+		assert.Equal(t, 0, stats.Waiting)
+		assert.Greater(t, stats.MaxWait, 50*time.Millisecond)
+		assert.GreaterOrEqual(t, stats.TotalWait, stats.MaxWait)
+	})
+
+	t.Run("Waiting respects the context", func(t *testing.T) {
+		l := okrforjira.NewRateLimiter(0.001, 1)
+		assert.NoError(t, l.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+
+		ctx, cancel = context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		assert.ErrorIs(t, l.Wait(ctx), context.Canceled)
+
+		stats := l.Stats()
+		assert.Equal(t, int64(1), stats.Requests)
+		assert.Equal(t, 0, stats.Waiting)
+		assert.Equal(t, int64(0), stats.Delayed)
+		assert.Equal(t, time.Duration(0), stats.TotalWait)
+		assert.Equal(t, time.Duration(0), stats.MaxWait)
+	})
+
+	t.Run("Client requests go through the rate limiter", func(t *testing.T) {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+				Header:     make(http.Header),
+			}
+		})
+		l := okrforjira.NewRateLimiter(1000, 2)
+		c1 := okrforjira.NewClient(client, token, okrforjira.WithRateLimiter(l))
+		c2 := okrforjira.NewClient(client, token, okrforjira.WithRateLimiter(l))
+		ctx := context.Background()
+		_, err := c1.ObjectivesByIDs(ctx, []string{"1"}, nil)
+		assert.NoError(t, err)
+		_, err = c2.UpdateObjective(ctx, "1", "ON_TRACK", "")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), c1.RateLimiterStats().Requests)
+
+		c3 := okrforjira.NewClient(client, token, okrforjira.WithRateLimit(1000, 1))
+		_, err = c3.KeyResultsByIDs(ctx, []string{"1"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), c3.RateLimiterStats().Requests)
+		assert.Zero(t, okrforjira.NewClient(client, token).RateLimiterStats())
+	})
+}
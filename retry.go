@@ -0,0 +1,131 @@
+package okrforjira
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// RetryPolicy defines how failed requests are retried.
+// When a policy is set with WithRetryPolicy, it is applied automatically to
+// the idempotent export methods (ObjectivesByDate, ObjectivesByIDs,
+// KeyResultsByDate and KeyResultsByIDs). UpdateObjective and UpdateKeyResult
+// are only retried if RetryUpdates is true.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry.
+	// The delay doubles after each attempt.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, of the delay that is randomized.
+	Jitter float64
+	// RetryableStatusCodes are the HTTP status codes of the responses to retry.
+	RetryableStatusCodes []int
+	// RetryNetworkErrors enables the retry of requests that failed
+	// without a response, e.g. because the connection was reset.
+	RetryNetworkErrors bool
+	// RetryUpdates enables the retry of UpdateObjective and UpdateKeyResult.
+	RetryUpdates bool
+}
+
+// DefaultRetryPolicy returns a policy making up to 4 attempts with an
+// exponential backoff starting at 500ms, on network errors and
+// 429, 502, 503 and 504 responses.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryNetworkErrors: true,
+	}
+}
+
+// WithRetryPolicy enables the retry of failed requests.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *config) {
+		c.retryPolicy = &policy
+	}
+}
+
+// do calls send until it succeeds, the error is not retryable,
+// the maximum number of attempts is reached or ctx is done.
+func (p *RetryPolicy) do(ctx context.Context, send func() (*http.Response, error)) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		r, err := send()
+		if err == nil || attempt >= p.MaxAttempts || !p.retryable(ctx, err) {
+			return r, err
+		}
+
+		delay := p.backoff(attempt)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+			delay = apiErr.RetryAfter
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// There is no time left for another attempt.
+			return r, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (p *RetryPolicy) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return slices.Contains(p.RetryableStatusCodes, apiErr.StatusCode)
+	}
+	return p.RetryNetworkErrors && isNetworkError(err)
+}
+
+// isNetworkError reports whether err is a failure of the transport, as
+// opposed to a deterministic error such as an invalid URL or a rate limiter
+// that does not allow any request, which would fail again on retry.
+func isNetworkError(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		// http.NewRequest reports an invalid URL as a *url.Error with Op "parse".
+		return urlErr.Op != "parse"
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// backoff returns the delay to wait after the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		delay -= time.Duration(p.Jitter * rand.Float64() * float64(delay))
+	}
+	return delay
+}
@@ -0,0 +1,141 @@
+package okrforjira_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Retry(t *testing.T) {
+	ctx := context.Background()
+	policy := okrforjira.RetryPolicy{
+		MaxAttempts:          3,
+		BaseBackoff:          time.Millisecond,
+		MaxBackoff:           5 * time.Millisecond,
+		Jitter:               0.5,
+		RetryableStatusCodes: []int{429, 503},
+		RetryNetworkErrors:   true,
+	}
+
+	// newFlakyClient returns a client whose first responses have the given status codes,
+	// and then succeed. A status code of 0 simulates a network error.
+	newFlakyClient := func(attempts *int, retryAfter string, statusCodes ...int) *http.Client {
+		return NewTestClient(func(req *http.Request) *http.Response {
+			*attempts++
+			statusCode := 200
+			if *attempts <= len(statusCodes) {
+				statusCode = statusCodes[*attempts-1]
+			}
+			if statusCode == 0 {
+				return nil
+			}
+			header := make(http.Header)
+			if retryAfter != "" {
+				header.Set("Retry-After", retryAfter)
+			}
+			return &http.Response{
+				StatusCode: statusCode,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+				Header:     header,
+			}
+		})
+	}
+
+	t.Run("Exports are retried on retryable status codes and network errors", func(t *testing.T) {
+		attempts := 0
+		c := okrforjira.NewClient(newFlakyClient(&attempts, "", 503, 0), token, okrforjira.WithRetryPolicy(policy))
+		_, err := c.ObjectivesByIDs(ctx, []string{"1"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("The last error is returned after the maximum number of attempts", func(t *testing.T) {
+		attempts := 0
+		c := okrforjira.NewClient(newFlakyClient(&attempts, "", 503, 503, 429, 429), token, okrforjira.WithRetryPolicy(policy))
+		_, err := c.KeyResultsByIDs(ctx, []string{"1"}, nil)
+		assert.ErrorIs(t, err, okrforjira.ErrRateLimited)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("Non retryable status codes are not retried", func(t *testing.T) {
+		attempts := 0
+		c := okrforjira.NewClient(newFlakyClient(&attempts, "", 404), token, okrforjira.WithRetryPolicy(policy))
+		_, err := c.ObjectivesByIDs(ctx, []string{"1"}, nil)
+		assert.ErrorIs(t, err, okrforjira.ErrNotFound)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("Network errors are not retried unless enabled", func(t *testing.T) {
+		attempts := 0
+		policy := policy
+		policy.RetryNetworkErrors = false
+		c := okrforjira.NewClient(newFlakyClient(&attempts, "", 0), token, okrforjira.WithRetryPolicy(policy))
+		_, err := c.ObjectivesByIDs(ctx, []string{"1"}, nil)
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("Invalid requests are not retried", func(t *testing.T) {
+		attempts := 0
+		// The rate limiter counts the attempts, which fail before reaching the transport.
+		limiter := okrforjira.NewRateLimiter(1000, 10)
+		c := okrforjira.NewClient(newFlakyClient(&attempts, ""), token,
+			okrforjira.WithRetryPolicy(okrforjira.DefaultRetryPolicy()),
+			okrforjira.WithRateLimiter(limiter),
+			okrforjira.WithBaseURL("http://[::1"))
+		_, err := c.ObjectivesByIDs(ctx, []string{"1"}, nil)
+		assert.Error(t, err)
+		assert.Equal(t, int64(1), limiter.Stats().Requests)
+		assert.Equal(t, 0, attempts)
+	})
+
+	t.Run("Updates are only retried on opt-in", func(t *testing.T) {
+		attempts := 0
+		c := okrforjira.NewClient(newFlakyClient(&attempts, "", 503), token, okrforjira.WithRetryPolicy(policy))
+		_, err := c.UpdateObjective(ctx, "1", "ON_TRACK", "")
+		assert.ErrorIs(t, err, okrforjira.ErrServer)
+		assert.Equal(t, 1, attempts)
+
+		attempts = 0
+		policy := policy
+		policy.RetryUpdates = true
+		c = okrforjira.NewClient(newFlakyClient(&attempts, "", 503), token, okrforjira.WithRetryPolicy(policy))
+		_, err = c.UpdateKeyResult(ctx, "1", "ON_TRACK", 1.0, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("Retry-After beyond the context deadline stops the retries", func(t *testing.T) {
+		attempts := 0
+		c := okrforjira.NewClient(newFlakyClient(&attempts, "3600", 429), token, okrforjira.WithRetryPolicy(policy))
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		start := time.Now()
+		_, err := c.ObjectivesByIDs(ctx, []string{"1"}, nil)
+		var apiErr *okrforjira.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, time.Hour, apiErr.RetryAfter)
+		assert.Equal(t, 1, attempts)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Cancelling the context interrupts the backoff", func(t *testing.T) {
+		attempts := 0
+		policy := policy
+		policy.BaseBackoff = time.Hour
+		policy.MaxBackoff = time.Hour
+		c := okrforjira.NewClient(newFlakyClient(&attempts, "", 503), token, okrforjira.WithRetryPolicy(policy))
+		ctx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, err := c.ObjectivesByIDs(ctx, []string{"1"}, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, attempts)
+	})
+}
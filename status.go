@@ -0,0 +1,95 @@
+package okrforjira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Status is the status of an objective or a key result set by a check-in.
+type Status string
+
+// The statuses known by OKR for Jira.
+const (
+	StatusOnTrack    Status = "ON_TRACK"
+	StatusAtRisk     Status = "AT_RISK"
+	StatusDelayed    Status = "DELAYED"
+	StatusNotStarted Status = "NOT_STARTED"
+	StatusUndefined  Status = "UNDEFINED"
+)
+
+// Statuses lists the statuses known by OKR for Jira.
+var Statuses = []Status{StatusOnTrack, StatusAtRisk, StatusDelayed, StatusNotStarted, StatusUndefined}
+
+// ErrUnknownStatus is returned when a status is not one of Statuses.
+var ErrUnknownStatus = errors.New("unknown status")
+
+// ParseStatus returns the status matching s.
+// The matching ignores the case and accepts spaces or hyphens instead of
+// underscores, so that "ON TRACK", "on-track" and "ON_TRACK" are all StatusOnTrack.
+func ParseStatus(s string) (Status, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(s))
+	normalized = strings.NewReplacer(" ", "_", "-", "_").Replace(normalized)
+	for _, status := range Statuses {
+		if Status(normalized) == status {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q", ErrUnknownStatus, s)
+}
+
+// String implements the fmt.Stringer interface.
+func (s Status) String() string {
+	return string(s)
+}
+
+// Validate returns an error if the status is not one of Statuses.
+// Alternative spellings accepted by ParseStatus are valid.
+func (s Status) Validate() error {
+	_, err := ParseStatus(string(s))
+	return err
+}
+
+// updateSpelling returns the status as spelled by the update endpoints, which
+// use spaces instead of underscores, e.g. "ON TRACK". The status must be valid.
+func (s Status) updateSpelling() string {
+	status, _ := ParseStatus(string(s))
+	return strings.ReplaceAll(string(status), "_", " ")
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The status is encoded with its canonical spelling, e.g. "ON_TRACK".
+// An unknown status, as decoded from the API, is encoded as is.
+func (s Status) MarshalJSON() ([]byte, error) {
+	if s == "" {
+		return []byte("null"), nil
+	}
+	if status, err := ParseStatus(string(s)); err == nil {
+		s = status
+	}
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Both "ON_TRACK" and "ON TRACK" are decoded as StatusOnTrack,
+// and a null status is decoded as an empty status. An unknown status is
+// kept as is, so that a new status of the API does not fail the decoding;
+// it is reported as a DriftUnknownValue, and Validate returns an error.
+func (s *Status) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid status %s: %w", data, err)
+	}
+	if value == nil || *value == "" {
+		*s = ""
+		return nil
+	}
+	status, err := ParseStatus(*value)
+	if err != nil {
+		*s = Status(*value)
+		return nil
+	}
+	*s = status
+	return nil
+}
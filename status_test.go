@@ -0,0 +1,95 @@
+package okrforjira_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		input string
+		want  okrforjira.Status
+	}{
+		{"ON_TRACK", okrforjira.StatusOnTrack},
+		{"ON TRACK", okrforjira.StatusOnTrack},
+		{"on-track", okrforjira.StatusOnTrack},
+		{"AT RISK", okrforjira.StatusAtRisk},
+		{"DELAYED", okrforjira.StatusDelayed},
+		{" not started ", okrforjira.StatusNotStarted},
+		{"UNDEFINED", okrforjira.StatusUndefined},
+	}
+	for _, tt := range tests {
+		got, err := okrforjira.ParseStatus(tt.input)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := okrforjira.ParseStatus("DONE")
+	assert.ErrorIs(t, err, okrforjira.ErrUnknownStatus)
+	assert.EqualError(t, err, `unknown status "DONE"`)
+}
+
+func TestStatus_JSON(t *testing.T) {
+	t.Run("Unmarshal", func(t *testing.T) {
+		var update struct {
+			Status okrforjira.Status `json:"status"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(`{"status":"AT RISK"}`), &update))
+		assert.Equal(t, okrforjira.StatusAtRisk, update.Status)
+
+		assert.NoError(t, json.Unmarshal([]byte(`{"status":null}`), &update))
+		assert.Equal(t, okrforjira.Status(""), update.Status)
+
+		// An unknown status is kept as is.
+		assert.NoError(t, json.Unmarshal([]byte(`{"status":"ON_FIRE"}`), &update))
+		assert.Equal(t, okrforjira.Status("ON_FIRE"), update.Status)
+		assert.ErrorIs(t, update.Status.Validate(), okrforjira.ErrUnknownStatus)
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		data, err := json.Marshal(okrforjira.Status("on track"))
+		assert.NoError(t, err)
+		assert.Equal(t, `"ON_TRACK"`, string(data))
+
+		data, err = json.Marshal(okrforjira.Status("ON_FIRE"))
+		assert.NoError(t, err)
+		assert.Equal(t, `"ON_FIRE"`, string(data))
+	})
+}
+
+func TestClient_UpdateWithInvalidStatus(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Fatal("no request should be sent for an invalid status")
+		return nil
+	})
+	c := okrforjira.NewClient(client, token)
+	ctx := context.Background()
+
+	_, err := c.UpdateObjective(ctx, "62334eac00ee2b102e34fdb7", "ON FIRE", "")
+	assert.ErrorIs(t, err, okrforjira.ErrUnknownStatus)
+	_, err = c.UpdateKeyResult(ctx, "62384a6942adda046598b3bd", "", 1.0, "")
+	assert.ErrorIs(t, err, okrforjira.ErrUnknownStatus)
+}
+
+func TestClient_UnknownStatusFromServer(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return newJSONResponse(200, `{"okrs":[{"id":"1","latestUpdate":{"status":"ON_FIRE"}}]}`)
+	})
+	var report okrforjira.DriftReport
+	c := okrforjira.NewClient(client, token, okrforjira.WithDriftReport(&report))
+	resp, err := c.ObjectivesByIDs(context.Background(), []string{"1"}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, resp.OKRs, 1) {
+		assert.Equal(t, okrforjira.Status("ON_FIRE"), resp.OKRs[0].LatestUpdate.Status)
+	}
+	want := []okrforjira.Drift{
+		{Kind: okrforjira.DriftUnknownValue, Entity: "Update", Field: "status", Expected: "status", Got: "ON_FIRE", Count: 1},
+	}
+	assert.Equal(t, want, report.Drifts())
+	assert.EqualError(t, report.Err(), `API schema drift: Update.status: unknown status "ON_FIRE" (1 times)`)
+}
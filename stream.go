@@ -0,0 +1,283 @@
+package okrforjira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// StreamHandler receives the objects of an export response one at a time
+// while the response is decoded. Objects of a type whose callback is nil are
+// skipped. Returning an error from a callback stops the decoding and the
+// error is returned by the streaming function.
+type StreamHandler struct {
+	OKR       func(OKR) error
+	KeyResult func(KeyResult) error
+	Team      func(Team) error
+	Period    func(Period) error
+	Label     func(Label) error
+}
+
+// DecodeStream decodes an export response from r and calls the callbacks of
+// h for each decoded object, in the order they appear in the response.
+// Only one object is held in memory at a time.
+func DecodeStream(r io.Reader, h StreamHandler) error {
+	return h.decode(r, &streamState{})
+}
+
+// decode decodes the response read from r. The objects whose ID is already
+// in the state are skipped, which deduplicates the objects streamed from
+// several responses like MergeResponses does.
+func (h StreamHandler) decode(r io.Reader, state *streamState) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("unexpected token %v", token)
+		}
+		switch key {
+		case "okrs":
+			err = streamArray(dec, h.OKR, state.drift, state.okrs, func(o OKR) string { return o.ID })
+		case "krs":
+			err = streamArray(dec, h.KeyResult, state.drift, state.keyResults, func(kr KeyResult) string { return kr.ID })
+		case "teams":
+			err = streamArray(dec, h.Team, state.drift, state.teams, func(t Team) string { return t.ID })
+		case "periods":
+			err = streamArray(dec, h.Period, state.drift, state.periods, func(p Period) string { return p.ID })
+		case "labels":
+			err = streamArray(dec, h.Label, state.drift, state.labels, func(l Label) string { return l.ID })
+		default:
+			err = skipUnknown(dec, state.drift, key)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// streamArray decodes the array at the current position of dec and calls fn
+// for each element. A null array is accepted.
+func streamArray[T any](dec *json.Decoder, fn func(T) error, drift *driftChecker, seen map[string]bool, id func(T) string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if token != json.Delim('[') {
+		return fmt.Errorf("unexpected token %v", token)
+	}
+	for dec.More() {
+		if fn == nil {
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+			continue
+		}
+		var item T
+		if err := decodeItem(dec, drift, &item); err != nil {
+			return err
+		}
+		if seen != nil {
+			if seen[id(item)] {
+				continue
+			}
+			seen[id(item)] = true
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// decodeItem decodes the value at the current position of dec into v,
+// checking it for drift first when drift is not nil.
+func decodeItem(dec *json.Decoder, drift *driftChecker, v interface{}) error {
+	if drift == nil {
+		return dec.Decode(v)
+	}
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	if err := drift.check(raw, reflect.TypeOf(v).Elem()); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// skipUnknown skips the value of an unknown key of the response,
+// recording it as a drift when drift is not nil.
+func skipUnknown(dec *json.Decoder, drift *driftChecker, key string) error {
+	if drift == nil {
+		return skipValue(dec)
+	}
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+	return drift.add(Drift{Kind: DriftUnknownField, Entity: responseType.Name(), Field: key, Got: jsonType(value), Count: 1})
+}
+
+// skipValue reads the value at the current position of dec without decoding it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected token %v, expected %v", token, delim)
+	}
+	return nil
+}
+
+// streamState holds the IDs of the objects already streamed by a query split
+// in several requests, nil maps disable the deduplication, and the drift
+// checker of the client, if any.
+type streamState struct {
+	okrs, keyResults, teams, periods, labels map[string]bool
+	drift                                    *driftChecker
+}
+
+func newStreamState() *streamState {
+	return &streamState{
+		okrs:       make(map[string]bool),
+		keyResults: make(map[string]bool),
+		teams:      make(map[string]bool),
+		periods:    make(map[string]bool),
+		labels:     make(map[string]bool),
+	}
+}
+
+// StreamObjectivesByDate is like ObjectivesByDateExpand but streams the
+// objects of the response to h instead of returning them.
+// When the range is split in several requests, the requests are sent one
+// after the other and the objects already streamed are skipped.
+func (c *Client) StreamObjectivesByDate(ctx context.Context, startDate, deadline time.Time, h StreamHandler, expand ...ExpandObject) error {
+	if err := c.streamByDate(ctx, objectivesByDatePath, startDate, deadline, h, expand); err != nil {
+		return fmt.Errorf("failed to stream the obectives by date: %w", err)
+	}
+	return nil
+}
+
+// StreamObjectivesByIDs is like ObjectivesByIDsExpand but streams the
+// objects of the response to h instead of returning them.
+// When the ids are split in several requests, the requests are sent one
+// after the other and the objects already streamed are skipped.
+func (c *Client) StreamObjectivesByIDs(ctx context.Context, objectiveIDs []string, h StreamHandler, expand ...ExpandObject) error {
+	if err := c.streamByIDs(ctx, objectivesByIDsPath, objectiveIDs, h, expand); err != nil {
+		return fmt.Errorf("failed to stream the obectives by ids: %w", err)
+	}
+	return nil
+}
+
+// StreamKeyResultsByDate is like KeyResultsByDateExpand but streams the
+// objects of the response to h instead of returning them.
+// When the range is split in several requests, the requests are sent one
+// after the other and the objects already streamed are skipped.
+func (c *Client) StreamKeyResultsByDate(ctx context.Context, startDate, deadline time.Time, h StreamHandler, expand ...ExpandObject) error {
+	if err := c.streamByDate(ctx, keyResultsByDatePath, startDate, deadline, h, expand); err != nil {
+		return fmt.Errorf("failed to stream the key results by date: %w", err)
+	}
+	return nil
+}
+
+// StreamKeyResultsByIDs is like KeyResultsByIDsExpand but streams the
+// objects of the response to h instead of returning them.
+// When the ids are split in several requests, the requests are sent one
+// after the other and the objects already streamed are skipped.
+func (c *Client) StreamKeyResultsByIDs(ctx context.Context, keyResultIDs []string, h StreamHandler, expand ...ExpandObject) error {
+	if err := c.streamByIDs(ctx, keyResultsByIDsPath, keyResultIDs, h, expand); err != nil {
+		return fmt.Errorf("failed to stream the key results by ids: %w", err)
+	}
+	return nil
+}
+
+func (c Client) streamByDate(ctx context.Context, path string, startDate, deadline time.Time, h StreamHandler, expand []ExpandObject) error {
+	expandParam, err := expandQueryParam(expand)
+	if err != nil {
+		return err
+	}
+	ranges := c.dateRanges(startDate, deadline)
+	urls := make([]string, len(ranges))
+	for i, r := range ranges {
+		urls[i] = fmt.Sprintf(c.baseURL+path, r.StartDate.UnixMilli(), r.Deadline.UnixMilli(), expandParam)
+	}
+	return c.streamAll(ctx, urls, h)
+}
+
+func (c Client) streamByIDs(ctx context.Context, path string, ids []string, h StreamHandler, expand []ExpandObject) error {
+	expandParam, err := expandQueryParam(expand)
+	if err != nil {
+		return err
+	}
+	batches := splitIDs(ids, c.idBatchSize)
+	urls := make([]string, len(batches))
+	for i, batch := range batches {
+		urls[i] = fmt.Sprintf(c.baseURL+path, strings.Join(batch, ","), expandParam)
+	}
+	return c.streamAll(ctx, urls, h)
+}
+
+func (c Client) streamAll(ctx context.Context, urls []string, h StreamHandler) error {
+	if c.location != nil {
+		h = h.inLocation(c.location)
+	}
+	state := &streamState{}
+	if len(urls) > 1 {
+		state = newStreamState()
+	}
+	state.drift = c.driftChecker()
+	for _, url := range urls {
+		if err := c.executeStreamQuery(ctx, url, h, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c Client) executeStreamQuery(ctx context.Context, url string, h StreamHandler, state *streamState) error {
+	r, err := c.execute(ctx, "GET", url, "", c.retryPolicy != nil)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if err := h.decode(r.Body, state); err != nil {
+		return transportError(ctx, err)
+	}
+	return nil
+}
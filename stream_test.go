@@ -0,0 +1,183 @@
+package okrforjira_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+// collect returns a handler appending every streamed object to r.
+func collect(r *okrforjira.Response) okrforjira.StreamHandler {
+	return okrforjira.StreamHandler{
+		OKR:       func(o okrforjira.OKR) error { r.OKRs = append(r.OKRs, o); return nil },
+		KeyResult: func(kr okrforjira.KeyResult) error { r.KeyResults = append(r.KeyResults, kr); return nil },
+		Team:      func(t okrforjira.Team) error { r.Teams = append(r.Teams, t); return nil },
+		Period:    func(p okrforjira.Period) error { r.Periods = append(r.Periods, p); return nil },
+		Label:     func(l okrforjira.Label) error { r.Labels = append(r.Labels, l); return nil },
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	t.Run("Objects are streamed in order", func(t *testing.T) {
+		body := `{
+			"okrs": [{"id": "o1"}, {"id": "o2"}],
+			"unknown": {"nested": [1, 2, {"a": null}]},
+			"krs": null,
+			"teams": [{"id": "t1", "name": "Team"}],
+			"labels": []
+		}`
+		var got okrforjira.Response
+		err := okrforjira.DecodeStream(strings.NewReader(body), collect(&got))
+		assert.NoError(t, err)
+		want := okrforjira.Response{
+			OKRs:  []okrforjira.OKR{{ID: "o1"}, {ID: "o2"}},
+			Teams: []okrforjira.Team{{ID: "t1", Name: "Team"}},
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("Objects without callback are skipped", func(t *testing.T) {
+		body := `{"okrs": [{"id": "o1"}], "teams": [{"id": "t1"}]}`
+		var teams []string
+		err := okrforjira.DecodeStream(strings.NewReader(body), okrforjira.StreamHandler{
+			Team: func(team okrforjira.Team) error { teams = append(teams, team.ID); return nil },
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"t1"}, teams)
+	})
+
+	t.Run("Callback error stops the decoding", func(t *testing.T) {
+		body := `{"okrs": [{"id": "o1"}, {"id": "o2"}]}`
+		errStop := errors.New("stop")
+		var okrs []string
+		err := okrforjira.DecodeStream(strings.NewReader(body), okrforjira.StreamHandler{
+			OKR: func(o okrforjira.OKR) error { okrs = append(okrs, o.ID); return errStop },
+		})
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, []string{"o1"}, okrs)
+	})
+
+	t.Run("Malformed response", func(t *testing.T) {
+		for _, body := range []string{``, `[]`, `{"okrs": {}}`, `{"okrs": [{"id": 1}]}`, `{"okrs": [`} {
+			var got okrforjira.Response
+			err := okrforjira.DecodeStream(strings.NewReader(body), collect(&got))
+			assert.Error(t, err, body)
+		}
+	})
+}
+
+func TestClient_Stream(t *testing.T) {
+	ctx := context.Background()
+	startDate := time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	fixtures := []struct {
+		name    string
+		fixture string
+		fetch   func(c *okrforjira.Client) (okrforjira.Response, error)
+		stream  func(c *okrforjira.Client, h okrforjira.StreamHandler) error
+	}{
+		{
+			name:    "ObjectivesByDate",
+			fixture: "objectives_by_date.json",
+			fetch: func(c *okrforjira.Client) (okrforjira.Response, error) {
+				return c.ObjectivesByDateExpand(ctx, startDate, deadline, okrforjira.ExpandAll()...)
+			},
+			stream: func(c *okrforjira.Client, h okrforjira.StreamHandler) error {
+				return c.StreamObjectivesByDate(ctx, startDate, deadline, h, okrforjira.ExpandAll()...)
+			},
+		},
+		{
+			name:    "ObjectivesByIDs",
+			fixture: "objectives_by_ids.json",
+			fetch: func(c *okrforjira.Client) (okrforjira.Response, error) {
+				return c.ObjectivesByIDsExpand(ctx, []string{"1"}, okrforjira.ExpandAll()...)
+			},
+			stream: func(c *okrforjira.Client, h okrforjira.StreamHandler) error {
+				return c.StreamObjectivesByIDs(ctx, []string{"1"}, h, okrforjira.ExpandAll()...)
+			},
+		},
+		{
+			name:    "KeyResultsByDate",
+			fixture: "key_results_by_date.json",
+			fetch: func(c *okrforjira.Client) (okrforjira.Response, error) {
+				return c.KeyResultsByDateExpand(ctx, startDate, deadline, okrforjira.ExpandAll()...)
+			},
+			stream: func(c *okrforjira.Client, h okrforjira.StreamHandler) error {
+				return c.StreamKeyResultsByDate(ctx, startDate, deadline, h, okrforjira.ExpandAll()...)
+			},
+		},
+		{
+			name:    "KeyResultsByIDs",
+			fixture: "key_results_by_ids.json",
+			fetch: func(c *okrforjira.Client) (okrforjira.Response, error) {
+				return c.KeyResultsByIDsExpand(ctx, []string{"1"}, okrforjira.ExpandAll()...)
+			},
+			stream: func(c *okrforjira.Client, h okrforjira.StreamHandler) error {
+				return c.StreamKeyResultsByIDs(ctx, []string{"1"}, h, okrforjira.ExpandAll()...)
+			},
+		},
+	}
+	for _, tt := range fixtures {
+		t.Run(tt.name+" streams the same objects", func(t *testing.T) {
+			body := string(readFixture(t, tt.fixture))
+			c := okrforjira.NewClient(NewTestClient(func(req *http.Request) *http.Response {
+				return newJSONResponse(200, body)
+			}), token)
+			want, err := tt.fetch(c)
+			assert.NoError(t, err)
+
+			var got okrforjira.Response
+			err = tt.stream(c, collect(&got))
+			assert.NoError(t, err)
+			// Empty arrays are not streamed at all.
+			opt := cmpopts.EquateEmpty()
+			assert.True(t, cmp.Equal(want, got, opt), cmp.Diff(want, got, opt))
+		})
+	}
+
+	t.Run("Batches are streamed without duplicates", func(t *testing.T) {
+		var queries []string
+		c := okrforjira.NewClient(NewTestClient(func(req *http.Request) *http.Response {
+			param := req.URL.Query().Get("objectiveIds")
+			queries = append(queries, param)
+			var okrs []string
+			for _, id := range strings.Split(param, ",") {
+				okrs = append(okrs, fmt.Sprintf(`{"id":%q}`, id))
+			}
+			return newJSONResponse(200, `{"okrs":[`+strings.Join(okrs, ",")+`],"teams":[{"id":"t1","name":"Team"}]}`)
+		}), token, okrforjira.WithIDBatchSize(2))
+
+		var got okrforjira.Response
+		err := c.StreamObjectivesByIDs(ctx, []string{"1", "2", "3", "2"}, collect(&got), okrforjira.ExpandTeams)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1,2", "3,2"}, queries)
+		assert.Equal(t, []okrforjira.OKR{{ID: "1"}, {ID: "2"}, {ID: "3"}}, got.OKRs)
+		assert.Equal(t, []okrforjira.Team{{ID: "t1", Name: "Team"}}, got.Teams)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		c := okrforjira.NewClient(NewTestClient(func(req *http.Request) *http.Response {
+			return newJSONResponse(404, `{}`)
+		}), token)
+		err := c.StreamKeyResultsByIDs(ctx, []string{"1"}, okrforjira.StreamHandler{})
+		assert.ErrorIs(t, err, okrforjira.ErrNotFound)
+	})
+
+	t.Run("Invalid expand", func(t *testing.T) {
+		c := okrforjira.NewClient(NewTestClient(func(req *http.Request) *http.Response {
+			t.Fatal("no request expected")
+			return nil
+		}), token)
+		err := c.StreamObjectivesByDate(ctx, startDate, deadline, okrforjira.StreamHandler{}, "INVALID")
+		assert.Error(t, err)
+	})
+}
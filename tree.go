@@ -0,0 +1,254 @@
+package okrforjira
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/slices"
+)
+
+// Tree is the hierarchy of the objectives of a Response, built from their ParentObjectiveID.
+type Tree struct {
+	// Roots are the objectives without parent, and the orphans.
+	Roots []*Node
+	// Issues are the inconsistencies found while building the tree.
+	Issues []TreeIssue
+
+	nodes map[string]*Node
+}
+
+// Node is an objective in a Tree.
+type Node struct {
+	OKR      *OKR
+	Parent   *Node
+	Children []*Node
+}
+
+// TreeIssueKind is the kind of a TreeIssue.
+type TreeIssueKind string
+
+// The kinds of issues found while building a Tree.
+const (
+	// TreeIssueCycle reports an objective that is its own ancestor.
+	TreeIssueCycle TreeIssueKind = "CYCLE"
+	// TreeIssueOrphan reports an objective whose parent is not in the response.
+	TreeIssueOrphan TreeIssueKind = "ORPHAN"
+	// TreeIssueDanglingChild reports a child objective ID that is not in the response.
+	TreeIssueDanglingChild TreeIssueKind = "DANGLING_CHILD"
+	// TreeIssueMismatch reports an objective whose ChildObjectiveIDs disagree
+	// with the ParentObjectiveID of its children.
+	TreeIssueMismatch TreeIssueKind = "MISMATCH"
+)
+
+// TreeIssue is an inconsistency in the hierarchy of the objectives.
+type TreeIssue struct {
+	Kind TreeIssueKind
+	// ObjectiveID is the ID of the objective with the issue.
+	ObjectiveID string
+	// RelatedID is the ID of the other objective involved, if any.
+	RelatedID string
+	Message   string
+}
+
+// NewTree builds the tree of the objectives of the response.
+// The tree points to the objectives of the response,
+// which must not be modified while the tree is used.
+func NewTree(r Response) *Tree {
+	t := &Tree{nodes: make(map[string]*Node, len(r.OKRs))}
+	for i := range r.OKRs {
+		t.nodes[r.OKRs[i].ID] = &Node{OKR: &r.OKRs[i]}
+	}
+
+	for i := range r.OKRs {
+		okr := &r.OKRs[i]
+		node := t.nodes[okr.ID]
+		if okr.ParentObjectiveID == "" {
+			t.Roots = append(t.Roots, node)
+			continue
+		}
+		parent, ok := t.nodes[okr.ParentObjectiveID]
+		if !ok {
+			t.Roots = append(t.Roots, node)
+			t.addIssue(TreeIssueOrphan, okr, okr.ParentObjectiveID, "parent objective %s is not in the response", okr.ParentObjectiveID)
+			continue
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+		if !slices.Contains(parent.OKR.ChildObjectiveIDs, okr.ID) {
+			t.addIssue(TreeIssueMismatch, parent.OKR, okr.ID, "objective %s has %s as parent but is not one of its children", objectiveName(okr), objectiveName(parent.OKR))
+		}
+	}
+
+	for i := range r.OKRs {
+		okr := &r.OKRs[i]
+		for _, childID := range okr.ChildObjectiveIDs {
+			child, ok := t.nodes[childID]
+			if !ok {
+				t.addIssue(TreeIssueDanglingChild, okr, childID, "child objective %s is not in the response", childID)
+				continue
+			}
+			if child.OKR.ParentObjectiveID != okr.ID {
+				t.addIssue(TreeIssueMismatch, okr, childID, "child objective %s has not %s as parent", objectiveName(child.OKR), objectiveName(okr))
+			}
+		}
+	}
+
+	t.detectCycles(r)
+	return t
+}
+
+// detectCycles reports the objectives that cannot be reached from a root,
+// which is only possible if they are part of a cycle.
+func (t *Tree) detectCycles(r Response) {
+	reachable := make(map[*Node]bool, len(t.nodes))
+	for _, root := range t.Roots {
+		walkDepthFirst(root, 0, reachable, func(*Node, int) bool { return true })
+	}
+	reported := make(map[*Node]bool)
+	for i := range r.OKRs {
+		node := t.nodes[r.OKRs[i].ID]
+		if reachable[node] || reported[node] {
+			continue
+		}
+		// Follow the parents until a node is seen twice to find the cycle.
+		seen := make(map[*Node]bool)
+		for !seen[node] {
+			seen[node] = true
+			node = node.Parent
+		}
+		if !reported[node] {
+			start := node
+			for {
+				reported[node] = true
+				t.addIssue(TreeIssueCycle, node.OKR, node.Parent.OKR.ID, "objective %s is its own ancestor", objectiveName(node.OKR))
+				node = node.Parent
+				if node == start {
+					break
+				}
+			}
+		}
+		// The objectives hanging from the cycle are not part of it.
+		for n := range seen {
+			reported[n] = true
+		}
+	}
+}
+
+func (t *Tree) addIssue(kind TreeIssueKind, okr *OKR, relatedID string, format string, args ...interface{}) {
+	t.Issues = append(t.Issues, TreeIssue{
+		Kind:        kind,
+		ObjectiveID: okr.ID,
+		RelatedID:   relatedID,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+// Node returns the node of the objective with the given ID.
+func (t *Tree) Node(id string) (*Node, bool) {
+	node, ok := t.nodes[id]
+	return node, ok
+}
+
+// Orphans returns the objectives whose parent is not in the response.
+func (t *Tree) Orphans() []*Node {
+	var orphans []*Node
+	for _, root := range t.Roots {
+		if root.OKR.ParentObjectiveID != "" {
+			orphans = append(orphans, root)
+		}
+	}
+	return orphans
+}
+
+// DepthFirst calls fn for each objective reachable from the roots in depth-first order,
+// with the depth of the objective, 0 for the roots. The children of an objective are
+// skipped if fn returns false.
+func (t *Tree) DepthFirst(fn func(node *Node, depth int) bool) {
+	visited := make(map[*Node]bool, len(t.nodes))
+	for _, root := range t.Roots {
+		walkDepthFirst(root, 0, visited, fn)
+	}
+}
+
+// BreadthFirst calls fn for each objective reachable from the roots in breadth-first order,
+// with the depth of the objective, 0 for the roots. The children of an objective are
+// skipped if fn returns false.
+func (t *Tree) BreadthFirst(fn func(node *Node, depth int) bool) {
+	type item struct {
+		node  *Node
+		depth int
+	}
+	visited := make(map[*Node]bool, len(t.nodes))
+	queue := make([]item, 0, len(t.Roots))
+	for _, root := range t.Roots {
+		queue = append(queue, item{node: root})
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current.node] {
+			continue
+		}
+		visited[current.node] = true
+		if !fn(current.node, current.depth) {
+			continue
+		}
+		for _, child := range current.node.Children {
+			queue = append(queue, item{node: child, depth: current.depth + 1})
+		}
+	}
+}
+
+// Ancestors returns the ancestors of the objective with the given ID,
+// starting with its parent.
+func (t *Tree) Ancestors(id string) []*Node {
+	node, ok := t.nodes[id]
+	if !ok {
+		return nil
+	}
+	var ancestors []*Node
+	visited := map[*Node]bool{node: true}
+	for node = node.Parent; node != nil && !visited[node]; node = node.Parent {
+		visited[node] = true
+		ancestors = append(ancestors, node)
+	}
+	return ancestors
+}
+
+// Descendants returns the descendants of the objective with the given ID
+// in depth-first order.
+func (t *Tree) Descendants(id string) []*Node {
+	node, ok := t.nodes[id]
+	if !ok {
+		return nil
+	}
+	var descendants []*Node
+	visited := map[*Node]bool{node: true}
+	for _, child := range node.Children {
+		walkDepthFirst(child, 1, visited, func(n *Node, _ int) bool {
+			descendants = append(descendants, n)
+			return true
+		})
+	}
+	return descendants
+}
+
+func walkDepthFirst(node *Node, depth int, visited map[*Node]bool, fn func(*Node, int) bool) {
+	if visited[node] {
+		return
+	}
+	visited[node] = true
+	if !fn(node, depth) {
+		return
+	}
+	for _, child := range node.Children {
+		walkDepthFirst(child, depth+1, visited, fn)
+	}
+}
+
+// objectiveName returns the key of the objective, or its ID if it has no key.
+func objectiveName(okr *OKR) string {
+	if okr.Key != "" {
+		return okr.Key
+	}
+	return okr.ID
+}
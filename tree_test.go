@@ -0,0 +1,111 @@
+package okrforjira_test
+
+import (
+	"testing"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func nodeKeys(nodes []*okrforjira.Node) []string {
+	keys := make([]string, len(nodes))
+	for i, node := range nodes {
+		keys[i] = node.OKR.Key
+	}
+	return keys
+}
+
+func TestTree(t *testing.T) {
+	// company
+	// ├── department A
+	// │   ├── team A1
+	// │   └── team A2
+	// └── department B
+	//     └── team B1
+	r := okrforjira.Response{
+		OKRs: []okrforjira.OKR{
+			{ID: "a1", Key: "O-4", ParentObjectiveID: "a"},
+			{ID: "company", Key: "O-1", ChildObjectiveIDs: []string{"a", "b"}},
+			{ID: "a", Key: "O-2", ParentObjectiveID: "company", ChildObjectiveIDs: []string{"a1", "a2"}},
+			{ID: "b", Key: "O-3", ParentObjectiveID: "company", ChildObjectiveIDs: []string{"b1"}},
+			{ID: "a2", Key: "O-5", ParentObjectiveID: "a"},
+			{ID: "b1", Key: "O-6", ParentObjectiveID: "b"},
+		},
+	}
+	tree := okrforjira.NewTree(r)
+	assert.Empty(t, tree.Issues)
+	assert.Empty(t, tree.Orphans())
+	assert.Equal(t, []string{"O-1"}, nodeKeys(tree.Roots))
+
+	t.Run("DepthFirst", func(t *testing.T) {
+		var keys []string
+		var depths []int
+		tree.DepthFirst(func(node *okrforjira.Node, depth int) bool {
+			keys = append(keys, node.OKR.Key)
+			depths = append(depths, depth)
+			return true
+		})
+		assert.Equal(t, []string{"O-1", "O-2", "O-4", "O-5", "O-3", "O-6"}, keys)
+		assert.Equal(t, []int{0, 1, 2, 2, 1, 2}, depths)
+	})
+
+	t.Run("BreadthFirst", func(t *testing.T) {
+		var keys []string
+		tree.BreadthFirst(func(node *okrforjira.Node, depth int) bool {
+			keys = append(keys, node.OKR.Key)
+			// Skip the teams of department B.
+			return node.OKR.Key != "O-3"
+		})
+		assert.Equal(t, []string{"O-1", "O-2", "O-3", "O-4", "O-5"}, keys)
+	})
+
+	t.Run("Ancestors and descendants", func(t *testing.T) {
+		assert.Equal(t, []string{"O-2", "O-1"}, nodeKeys(tree.Ancestors("a2")))
+		assert.Empty(t, tree.Ancestors("company"))
+		assert.Nil(t, tree.Ancestors("unknown"))
+		assert.Equal(t, []string{"O-2", "O-4", "O-5", "O-3", "O-6"}, nodeKeys(tree.Descendants("company")))
+		assert.Equal(t, []string{"O-6"}, nodeKeys(tree.Descendants("b")))
+		assert.Empty(t, tree.Descendants("b1"))
+	})
+
+	t.Run("Node", func(t *testing.T) {
+		node, ok := tree.Node("b1")
+		assert.True(t, ok)
+		assert.Equal(t, "O-3", node.Parent.OKR.Key)
+	})
+}
+
+func TestTree_Issues(t *testing.T) {
+	r := okrforjira.Response{
+		OKRs: []okrforjira.OKR{
+			{ID: "root", Key: "O-1", ChildObjectiveIDs: []string{"child", "missing", "other"}},
+			// The parent does not list this child.
+			{ID: "child", Key: "O-2", ParentObjectiveID: "root"},
+			{ID: "unlisted", Key: "O-3", ParentObjectiveID: "root"},
+			// The child listed by the root has another parent.
+			{ID: "other", Key: "O-4", ParentObjectiveID: "orphan"},
+			{ID: "orphan", Key: "O-5", ParentObjectiveID: "deleted", ChildObjectiveIDs: []string{"other"}},
+			// A cycle, with an objective hanging from it.
+			{ID: "c1", Key: "O-6", ParentObjectiveID: "c2", ChildObjectiveIDs: []string{"c2", "c3"}},
+			{ID: "c2", Key: "O-7", ParentObjectiveID: "c1", ChildObjectiveIDs: []string{"c1"}},
+			{ID: "c3", Key: "O-8", ParentObjectiveID: "c1"},
+		},
+	}
+	tree := okrforjira.NewTree(r)
+
+	assert.Equal(t, []string{"O-1", "O-5"}, nodeKeys(tree.Roots))
+	assert.Equal(t, []string{"O-5"}, nodeKeys(tree.Orphans()))
+	assert.Equal(t, []okrforjira.TreeIssue{
+		{Kind: okrforjira.TreeIssueMismatch, ObjectiveID: "root", RelatedID: "unlisted", Message: "objective O-3 has O-1 as parent but is not one of its children"},
+		{Kind: okrforjira.TreeIssueOrphan, ObjectiveID: "orphan", RelatedID: "deleted", Message: "parent objective deleted is not in the response"},
+		{Kind: okrforjira.TreeIssueDanglingChild, ObjectiveID: "root", RelatedID: "missing", Message: "child objective missing is not in the response"},
+		{Kind: okrforjira.TreeIssueMismatch, ObjectiveID: "root", RelatedID: "other", Message: "child objective O-4 has not O-1 as parent"},
+		{Kind: okrforjira.TreeIssueCycle, ObjectiveID: "c1", RelatedID: "c2", Message: "objective O-6 is its own ancestor"},
+		{Kind: okrforjira.TreeIssueCycle, ObjectiveID: "c2", RelatedID: "c1", Message: "objective O-7 is its own ancestor"},
+	}, tree.Issues)
+
+	// The traversals terminate despite the cycle.
+	assert.Equal(t, []string{"O-2", "O-3"}, nodeKeys(tree.Descendants("root")))
+	assert.Equal(t, []string{"O-7", "O-8"}, nodeKeys(tree.Descendants("c1")))
+	assert.Equal(t, []string{"O-6", "O-7"}, nodeKeys(tree.Ancestors("c3")))
+}
@@ -0,0 +1,282 @@
+package okrforjira
+
+import "fmt"
+
+// Severity is the severity of a Finding.
+type Severity string
+
+// The severities of the findings.
+const (
+	SeverityError   Severity = "ERROR"
+	SeverityWarning Severity = "WARNING"
+)
+
+// Finding is an inconsistency found in a Response by a Rule.
+type Finding struct {
+	Severity Severity
+	RuleID   string
+	// EntityID is the ID of the objective or key result with the inconsistency.
+	EntityID string
+	// EntityKey is the key of the objective or key result, e.g. "KR-8".
+	EntityKey string
+	Message   string
+}
+
+// String implements the fmt.Stringer interface.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", f.Severity, f.RuleID, f.EntityKey, f.Message)
+}
+
+// Rule is a consistency check of a Response.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+	// Check returns the findings of the rule. The RuleID and Severity of the
+	// findings are set by the Validator when they are empty.
+	Check func(r Response, idx *Index) []Finding
+}
+
+// IDs of the rules of DefaultRules.
+const (
+	RuleKeyResultParent       = "kr-parent"
+	RuleKeyResultListed       = "kr-listed"
+	RuleUnknownTeam           = "unknown-team"
+	RuleUnknownLabel          = "unknown-label"
+	RuleUnknownPeriod         = "unknown-period"
+	RuleStartAfterDeadline    = "start-after-deadline"
+	RuleKeyResultOutsideDates = "kr-outside-objective-dates"
+)
+
+// DefaultRules returns the rules checked by Validate.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			ID:          RuleKeyResultParent,
+			Severity:    SeverityError,
+			Description: "The key results listed by an objective have the objective as parent.",
+			Check:       checkKeyResultParent,
+		},
+		{
+			ID:          RuleKeyResultListed,
+			Severity:    SeverityWarning,
+			Description: "A key result is listed by its parent objective.",
+			Check:       checkKeyResultListed,
+		},
+		{
+			ID:          RuleUnknownTeam,
+			Severity:    SeverityWarning,
+			Description: "The teams of objectives and key results are in the expanded teams.",
+			Check:       checkUnknownTeam,
+		},
+		{
+			ID:          RuleUnknownLabel,
+			Severity:    SeverityWarning,
+			Description: "The labels of objectives and key results are in the expanded labels.",
+			Check:       checkUnknownLabel,
+		},
+		{
+			ID:          RuleUnknownPeriod,
+			Severity:    SeverityWarning,
+			Description: "The periods of objectives and key results are in the expanded periods.",
+			Check:       checkUnknownPeriod,
+		},
+		{
+			ID:          RuleStartAfterDeadline,
+			Severity:    SeverityError,
+			Description: "Objectives and key results start before their deadline.",
+			Check:       checkStartAfterDeadline,
+		},
+		{
+			ID:          RuleKeyResultOutsideDates,
+			Severity:    SeverityWarning,
+			Description: "Key results start and end within the dates of their objective.",
+			Check:       checkKeyResultOutsideDates,
+		},
+	}
+}
+
+// Validator checks the consistency of a Response with a set of rules.
+type Validator struct {
+	rules []Rule
+}
+
+// NewValidator creates a validator with the provided rules.
+// Use DefaultRules to start from the built-in rules.
+// It returns an error if a rule is rejected by Register.
+func NewValidator(rules ...Rule) (*Validator, error) {
+	v := &Validator{}
+	for _, rule := range rules {
+		if err := v.Register(rule); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Register adds a rule to the validator.
+// It returns an error if a rule with the same ID is already registered.
+func (v *Validator) Register(rule Rule) error {
+	if rule.Check == nil {
+		return fmt.Errorf("rule %s has no check", rule.ID)
+	}
+	for _, r := range v.rules {
+		if r.ID == rule.ID {
+			return fmt.Errorf("rule %s is already registered", rule.ID)
+		}
+	}
+	v.rules = append(v.rules, rule)
+	return nil
+}
+
+// Rules returns the registered rules.
+func (v *Validator) Rules() []Rule {
+	rules := make([]Rule, len(v.rules))
+	copy(rules, v.rules)
+	return rules
+}
+
+// Validate returns the findings of all the registered rules.
+func (v *Validator) Validate(r Response) []Finding {
+	idx := NewIndex(r)
+	var findings []Finding
+	for _, rule := range v.rules {
+		for _, f := range rule.Check(r, idx) {
+			if f.RuleID == "" {
+				f.RuleID = rule.ID
+			}
+			if f.Severity == "" {
+				f.Severity = rule.Severity
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// Validate checks the consistency of the response with DefaultRules.
+func Validate(r Response) []Finding {
+	v := &Validator{rules: DefaultRules()}
+	return v.Validate(r)
+}
+
+func okrFinding(okr OKR, format string, args ...interface{}) Finding {
+	return Finding{EntityID: okr.ID, EntityKey: objectiveName(&okr), Message: fmt.Sprintf(format, args...)}
+}
+
+func keyResultFinding(kr KeyResult, format string, args ...interface{}) Finding {
+	key := kr.Key
+	if key == "" {
+		key = kr.ID
+	}
+	return Finding{EntityID: kr.ID, EntityKey: key, Message: fmt.Sprintf(format, args...)}
+}
+
+func checkKeyResultParent(r Response, idx *Index) []Finding {
+	var findings []Finding
+	for _, okr := range r.OKRs {
+		for _, kr := range idx.KeyResultsOf(okr) {
+			if kr.ParentObjectiveID != okr.ID {
+				findings = append(findings, okrFinding(okr, "key result %s has objective %s as parent", kr.Key, kr.ParentObjectiveID))
+			}
+		}
+	}
+	return findings
+}
+
+func checkKeyResultListed(r Response, idx *Index) []Finding {
+	var findings []Finding
+	for _, kr := range r.KeyResults {
+		parent, ok := idx.ParentOf(kr)
+		if !ok {
+			continue
+		}
+		listed := false
+		for _, id := range parent.KRIDs {
+			listed = listed || id == kr.ID
+		}
+		if !listed {
+			findings = append(findings, keyResultFinding(kr, "key result is not listed by its objective %s", objectiveName(parent)))
+		}
+	}
+	return findings
+}
+
+// checkReferences reports the IDs returned by ids that are not found by lookup.
+// The check is skipped when expanded is false, as the referenced objects
+// are only part of the response when they are expanded.
+func checkReferences(r Response, expanded bool, kind string, ids func(Entity) []string, lookup func(string) bool) []Finding {
+	if !expanded {
+		return nil
+	}
+	var findings []Finding
+	for _, okr := range r.OKRs {
+		for _, id := range ids(okr) {
+			if id != "" && !lookup(id) {
+				findings = append(findings, okrFinding(okr, "%s %s is not in the response", kind, id))
+			}
+		}
+	}
+	for _, kr := range r.KeyResults {
+		for _, id := range ids(kr) {
+			if id != "" && !lookup(id) {
+				findings = append(findings, keyResultFinding(kr, "%s %s is not in the response", kind, id))
+			}
+		}
+	}
+	return findings
+}
+
+func checkUnknownTeam(r Response, idx *Index) []Finding {
+	return checkReferences(r, len(r.Teams) > 0, "team", Entity.teamIDs, func(id string) bool {
+		_, ok := idx.Team(id)
+		return ok
+	})
+}
+
+func checkUnknownLabel(r Response, idx *Index) []Finding {
+	return checkReferences(r, len(r.Labels) > 0, "label", Entity.labelIDs, func(id string) bool {
+		_, ok := idx.Label(id)
+		return ok
+	})
+}
+
+func checkUnknownPeriod(r Response, idx *Index) []Finding {
+	periodIDs := func(e Entity) []string { return []string{e.periodAliasID()} }
+	return checkReferences(r, len(r.Periods) > 0, "period", periodIDs, func(id string) bool {
+		_, ok := idx.Period(id)
+		return ok
+	})
+}
+
+func checkStartAfterDeadline(r Response, idx *Index) []Finding {
+	var findings []Finding
+	for _, okr := range r.OKRs {
+		if okr.StartDate.After(okr.Deadline) {
+			findings = append(findings, okrFinding(okr, "start date %s is after the deadline %s", okr.StartDate.Format(dateFormat), okr.Deadline.Format(dateFormat)))
+		}
+	}
+	for _, kr := range r.KeyResults {
+		if kr.StartDate.After(kr.Deadline) {
+			findings = append(findings, keyResultFinding(kr, "start date %s is after the deadline %s", kr.StartDate.Format(dateFormat), kr.Deadline.Format(dateFormat)))
+		}
+	}
+	return findings
+}
+
+func checkKeyResultOutsideDates(r Response, idx *Index) []Finding {
+	var findings []Finding
+	for _, kr := range r.KeyResults {
+		parent, ok := idx.ParentOf(kr)
+		if !ok {
+			continue
+		}
+		if kr.StartDate.Before(parent.StartDate) {
+			findings = append(findings, keyResultFinding(kr, "start date %s is before the start date %s of objective %s", kr.StartDate.Format(dateFormat), parent.StartDate.Format(dateFormat), objectiveName(parent)))
+		}
+		if kr.Deadline.After(parent.Deadline) {
+			findings = append(findings, keyResultFinding(kr, "deadline %s is after the deadline %s of objective %s", kr.Deadline.Format(dateFormat), parent.Deadline.Format(dateFormat), objectiveName(parent)))
+		}
+	}
+	return findings
+}
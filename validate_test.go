@@ -0,0 +1,106 @@
+package okrforjira_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grandper/okrforjira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	q1Start := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	q1End := time.Date(2021, time.March, 31, 23, 59, 59, 0, time.UTC)
+
+	t.Run("Consistent response", func(t *testing.T) {
+		assert.Empty(t, okrforjira.Validate(newTestResponse()))
+	})
+
+	t.Run("Inconsistent response", func(t *testing.T) {
+		r := okrforjira.Response{
+			OKRs: []okrforjira.OKR{
+				{
+					ID: "o1", Key: "O-1", StartDate: q1Start, Deadline: q1End,
+					KRIDs:   []string{"kr1", "kr2"},
+					TeamIDs: []string{"t1", "t9"},
+				},
+				{ID: "o2", Key: "O-2", StartDate: q1End, Deadline: q1Start, PeriodAliasID: "p9"},
+			},
+			KeyResults: []okrforjira.KeyResult{
+				{ID: "kr1", Key: "KR-1", ParentObjectiveID: "o1", StartDate: q1Start, Deadline: q1End},
+				{ID: "kr2", Key: "KR-2", ParentObjectiveID: "o2", StartDate: q1Start, Deadline: q1Start},
+				{
+					ID: "kr3", Key: "KR-3", ParentObjectiveID: "o1",
+					StartDate: q1Start.AddDate(0, -1, 0), Deadline: q1End.AddDate(0, 0, 1),
+					LabelIDs: []string{"l9"},
+				},
+			},
+			Teams:   []okrforjira.Team{{ID: "t1"}},
+			Periods: []okrforjira.Period{{ID: "p1"}},
+			// The labels are not expanded, so that their references are not checked.
+			Labels: []okrforjira.Label{},
+		}
+
+		got := okrforjira.Validate(r)
+		want := []okrforjira.Finding{
+			{Severity: okrforjira.SeverityError, RuleID: okrforjira.RuleKeyResultParent, EntityID: "o1", EntityKey: "O-1", Message: "key result KR-2 has objective o2 as parent"},
+			{Severity: okrforjira.SeverityWarning, RuleID: okrforjira.RuleKeyResultListed, EntityID: "kr2", EntityKey: "KR-2", Message: "key result is not listed by its objective O-2"},
+			{Severity: okrforjira.SeverityWarning, RuleID: okrforjira.RuleKeyResultListed, EntityID: "kr3", EntityKey: "KR-3", Message: "key result is not listed by its objective O-1"},
+			{Severity: okrforjira.SeverityWarning, RuleID: okrforjira.RuleUnknownTeam, EntityID: "o1", EntityKey: "O-1", Message: "team t9 is not in the response"},
+			{Severity: okrforjira.SeverityWarning, RuleID: okrforjira.RuleUnknownPeriod, EntityID: "o2", EntityKey: "O-2", Message: "period p9 is not in the response"},
+			{Severity: okrforjira.SeverityError, RuleID: okrforjira.RuleStartAfterDeadline, EntityID: "o2", EntityKey: "O-2", Message: "start date 2021-03-31T23:59:59+0000 is after the deadline 2021-01-01T00:00:00+0000"},
+			{Severity: okrforjira.SeverityWarning, RuleID: okrforjira.RuleKeyResultOutsideDates, EntityID: "kr2", EntityKey: "KR-2", Message: "start date 2021-01-01T00:00:00+0000 is before the start date 2021-03-31T23:59:59+0000 of objective O-2"},
+			{Severity: okrforjira.SeverityWarning, RuleID: okrforjira.RuleKeyResultOutsideDates, EntityID: "kr3", EntityKey: "KR-3", Message: "start date 2020-12-01T00:00:00+0000 is before the start date 2021-01-01T00:00:00+0000 of objective O-1"},
+			{Severity: okrforjira.SeverityWarning, RuleID: okrforjira.RuleKeyResultOutsideDates, EntityID: "kr3", EntityKey: "KR-3", Message: "deadline 2021-04-01T23:59:59+0000 is after the deadline 2021-03-31T23:59:59+0000 of objective O-1"},
+		}
+		assert.Equal(t, want, got)
+		assert.Equal(t, "ERROR [kr-parent] O-1: key result KR-2 has objective o2 as parent", got[0].String())
+	})
+}
+
+func TestValidator_Register(t *testing.T) {
+	v, err := okrforjira.NewValidator(okrforjira.DefaultRules()...)
+	assert.NoError(t, err)
+	err = v.Register(okrforjira.Rule{
+		ID:       "owner-required",
+		Severity: okrforjira.SeverityWarning,
+		Check: func(r okrforjira.Response, idx *okrforjira.Index) []okrforjira.Finding {
+			var findings []okrforjira.Finding
+			for _, kr := range r.KeyResults {
+				if kr.OwnerAccountID == "" {
+					findings = append(findings, okrforjira.Finding{EntityID: kr.ID, EntityKey: kr.Key, Message: "key result has no owner"})
+				}
+			}
+			return findings
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, v.Rules(), len(okrforjira.DefaultRules())+1)
+
+	got := v.Validate(newTestResponse())
+	assert.Len(t, got, 3)
+	assert.Equal(t, okrforjira.Finding{
+		Severity:  okrforjira.SeverityWarning,
+		RuleID:    "owner-required",
+		EntityID:  "kr1",
+		EntityKey: "KR-1",
+		Message:   "key result has no owner",
+	}, got[0])
+
+	assert.EqualError(t, v.Register(okrforjira.Rule{ID: okrforjira.RuleUnknownTeam, Check: okrforjira.DefaultRules()[0].Check}), "rule unknown-team is already registered")
+	assert.EqualError(t, v.Register(okrforjira.Rule{ID: "no-check"}), "rule no-check has no check")
+}
+
+func TestNewValidator(t *testing.T) {
+	check := okrforjira.DefaultRules()[0].Check
+
+	_, err := okrforjira.NewValidator(okrforjira.Rule{ID: "no-check"})
+	assert.EqualError(t, err, "rule no-check has no check")
+
+	_, err = okrforjira.NewValidator(okrforjira.Rule{ID: "twice", Check: check}, okrforjira.Rule{ID: "twice", Check: check})
+	assert.EqualError(t, err, "rule twice is already registered")
+
+	v, err := okrforjira.NewValidator()
+	assert.NoError(t, err)
+	assert.Empty(t, v.Validate(newTestResponse()))
+}